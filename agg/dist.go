@@ -0,0 +1,42 @@
+package agg
+
+import (
+	"math"
+
+	"github.com/sententico/cost/flt"
+)
+
+// Dist aggregator; merges Dist filtered partial column distributions (moments combined by Chan's
+// parallel algorithm, t-digests merged)
+func Dist(fin <-chan *flt.Dists) *flt.Dists {
+	var d *flt.Dists
+	for fr := range fin {
+		if d == nil {
+			d = fr
+			continue
+		}
+		for _, h := range fr.Cols {
+			c, fc := d.Col[h], fr.Col[h]
+			switch {
+			case c == nil:
+				d.Cols, d.Col[h] = append(d.Cols, h), fc
+				continue
+			case fc.N == 0:
+			case c.N == 0:
+				fc.Bad += c.Bad
+				d.Col[h] = fc
+				continue
+			default:
+				n := float64(c.N + fc.N)
+				dx := fc.Mean - c.Mean
+				c.M2 += fc.M2 + dx*dx*float64(c.N)*float64(fc.N)/n
+				c.Mean += dx * float64(fc.N) / n
+				c.N, c.Sum = c.N+fc.N, c.Sum+fc.Sum
+				c.Min, c.Max = math.Min(c.Min, fc.Min), math.Max(c.Max, fc.Max)
+				c.TD.Merge(fc.TD)
+			}
+			c.Bad += fc.Bad
+		}
+	}
+	return d
+}
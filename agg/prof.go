@@ -0,0 +1,49 @@
+package agg
+
+import (
+	"github.com/sententico/cost/flt"
+)
+
+// Prof aggregator; merges Prof filtered partial column profiles by column head
+func Prof(fin <-chan *flt.Profile) *flt.Profile {
+	var p *flt.Profile
+	for fr := range fin {
+		if p == nil {
+			p = fr
+			continue
+		}
+		for _, h := range fr.Cols {
+			c, fc := p.Col[h], fr.Col[h]
+			if c == nil {
+				p.Cols, p.Col[h] = append(p.Cols, h), fc
+				continue
+			}
+			c.N, c.Blank, c.E164 = c.N+fc.N, c.Blank+fc.Blank, c.E164+fc.E164
+			for k, n := range fc.Class {
+				c.Class[k] += n
+			}
+			if fc.MinLen >= 0 && (c.MinLen < 0 || fc.MinLen < c.MinLen) {
+				c.MinLen = fc.MinLen
+			}
+			if fc.MaxLen > c.MaxLen {
+				c.MaxLen = fc.MaxLen
+			}
+			c.Card.Merge(fc.Card)
+			for _, v := range fc.Vals {
+				if c.More {
+					break
+				}
+				i := 0
+				for ; i < len(c.Vals) && c.Vals[i] != v; i++ {
+				}
+				if i == len(c.Vals) && len(c.Vals) < flt.ProfVals {
+					c.Vals = append(c.Vals, v)
+				} else if i == len(c.Vals) {
+					c.More = true
+				}
+			}
+			c.More = c.More || fc.More
+		}
+	}
+	return p
+}
@@ -0,0 +1,42 @@
+package agg
+
+import (
+	"strconv"
+
+	"github.com/sententico/cost/flt"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+// Rate aggregator; merges Rate filtered partial re-rating totals by prefix (noting mixed
+// currencies), retaining failures (up to "fails" parameter)
+func Rate(fin <-chan *flt.Rating) *flt.Rating {
+	rt := &flt.Rating{Groups: make(map[string]*flt.RateGroup)}
+	max, _ := strconv.Atoi(pfax.Param("fails"))
+	for fr := range fin {
+		switch {
+		case rt.Currency == "":
+			rt.Currency = fr.Currency
+		case rt.Currency != fr.Currency:
+			rt.Currency = "mixed"
+		}
+		for p, g := range fr.Groups {
+			if rg, ok := rt.Groups[p]; ok {
+				rg.Calls += g.Calls
+				rg.BYOC += g.BYOC
+				rg.Mins += g.Mins
+				rg.Charged += g.Charged
+				rg.Rated += g.Rated
+			} else {
+				rt.Groups[p] = g
+			}
+		}
+		for _, f := range fr.Fails {
+			if len(rt.Fails) < max {
+				rt.Fails = append(rt.Fails, f)
+			}
+		}
+		rt.NFail += fr.NFail
+		rt.FailChg += fr.FailChg
+	}
+	return rt
+}
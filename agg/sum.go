@@ -0,0 +1,35 @@
+package agg
+
+import (
+	"strings"
+
+	"github.com/sententico/cost/flt"
+)
+
+// Sum aggregator; merges Sum filtered partial group-by sums (skipping partials with key or sum
+// columns inconsistent with the first)
+func Sum(fin <-chan *flt.Sums) *flt.Sums {
+	var s *flt.Sums
+	for fr := range fin {
+		switch {
+		case s == nil:
+			s = fr
+			continue
+		case strings.Join(fr.Keys, ",") != strings.Join(s.Keys, ",") || strings.Join(fr.Cols, ",") != strings.Join(s.Cols, ","):
+			s.Skip++
+			continue
+		}
+		for k, g := range fr.Groups {
+			if sg, ok := s.Groups[k]; ok {
+				for i, v := range g.Sums {
+					sg.Sums[i] += v
+				}
+				sg.Rows += g.Rows
+			} else {
+				s.Groups[k] = g
+			}
+		}
+		s.Bad += fr.Bad
+	}
+	return s
+}
@@ -1,22 +1,38 @@
-package agg
-
-import (
-	_ "github.com/sententico/cost/internal/pfax" // stub reference
-)
-
-// WC aggregator; aggregates WC filtered input for transformation
-func WC(fin <-chan interface{}) interface{} {
-	wc := make(map[string]map[string]int)
-	for fr := range fin {
-		for k, ke := range fr.(map[string]map[string]int) {
-			if wce, ok := wc[k]; ok {
-				for v, c := range ke {
-					wce[v] += c
-				}
-			} else {
-				wc[k] = ke
-			}
-		}
-	}
-	return wc
-}
+package agg
+
+import (
+	"github.com/sententico/cost/flt"
+	_ "github.com/sententico/cost/internal/pfax" // stub reference
+	"github.com/sententico/cost/internal/sketch"
+)
+
+// WC aggregator; aggregates WC filtered input for transformation
+func WC(fin <-chan *flt.Counts) *flt.Counts {
+	wc := &flt.Counts{}
+	for fr := range fin {
+		for k, ke := range fr.Vals {
+			if wc.Vals == nil {
+				wc.Vals = make(map[string]map[string]int)
+			}
+			if wce, ok := wc.Vals[k]; ok {
+				for v, c := range ke {
+					wce[v] += c
+				}
+			} else {
+				wc.Vals[k] = ke
+			}
+		}
+		for k, t := range fr.Top {
+			if wc.Top == nil {
+				wc.Top, wc.Card = make(map[string]*sketch.TopK), make(map[string]*sketch.HLL)
+			}
+			if wt, ok := wc.Top[k]; ok {
+				wt.Merge(t)
+				wc.Card[k].Merge(fr.Card[k])
+			} else {
+				wc.Top[k], wc.Card[k] = t, fr.Card[k]
+			}
+		}
+	}
+	return wc
+}
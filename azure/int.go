@@ -1 +1 @@
-package azure
+package azure
@@ -1,252 +1,518 @@
-package main
-
-import (
-	"flag"
-	"fmt"
-	"io"
-	"math"
-	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
-	"sync"
-	"time"
-
-	"github.com/sententico/cost/csv"
-	"github.com/sententico/cost/tel"
-)
-
-var (
-	settingsFlag string
-	csvFlag      bool
-	debugFlag    bool
-	rateFlag     bool
-	forceFlag    bool
-	colsFlag     string
-	wg           sync.WaitGroup
-)
-
-func init() {
-	// set up command-line flags
-	flag.StringVar(&settingsFlag, "s", "~/.csv_settings.json", fmt.Sprintf("file-type settings `file` containing column filter maps"))
-	flag.BoolVar(&forceFlag, "f", false, fmt.Sprintf("force file-type settings to settings file"))
-	flag.BoolVar(&csvFlag, "c", false, fmt.Sprintf("specify CSV output"))
-	flag.BoolVar(&debugFlag, "d", false, fmt.Sprintf("specify debug output"))
-	flag.BoolVar(&rateFlag, "r", false, fmt.Sprintf("specify call rating output"))
-	flag.StringVar(&colsFlag, "cols", "", fmt.Sprintf("column filter `map`: "+
-		"'[!]<head>[:(=|!){<pfx>[:<pfx>]...}][[:<bcol>]:<col>][,...]'  (ex. 'name,,!stat:={OK},age,acct:!{n/a:0000}:6')"))
-
-	// call on ErrHelp
-	flag.Usage = func() {
-		fmt.Printf("command usage: csv [-c] [-d] [-f] [-cols '<map>'] [-s <file>] <csvfile> [...]" +
-			"\n\nThis command identifies and parses CSV and fixed-field TXT files using column filter maps.\n\n")
-		flag.PrintDefaults()
-	}
-}
-
-func updateSettings(res *csv.Resource, cflag string, force bool) (cols string) {
-	switch cflag {
-	case "":
-		cols = res.Settings.Cols
-	case "*":
-	default:
-		if cols = cflag; res.Settings.Cols != cols {
-			res.Settings.Cols, res.Settings.Date = cols, time.Now()
-		}
-	}
-
-	switch res.Typ { // these initial settings should be manually updated
-	case csv.RTcsv:
-		if res.Sig == "" && force {
-			res.Sig, res.Settings.Date = fmt.Sprintf("=%s%d", string(res.Sep), len(res.Split[0])), time.Now()
-		}
-		if res.Settings.Format == "" && res.Settings.Ver == "" {
-			res.Settings.Format, res.Settings.Ver, res.Settings.Date = "unspecified CSV", res.Location, time.Now()
-		}
-	case csv.RTfixed:
-		if res.Sig == "" && force {
-			res.Sig, res.Settings.Date = fmt.Sprintf("=h%d,f%d", len(res.Preview[0]), len(res.Preview[1])), time.Now()
-		}
-		if res.Settings.Format == "" && res.Settings.Ver == "" {
-			res.Settings.Format, res.Settings.Ver, res.Settings.Date = "unspecified fixed-field", res.Location, time.Now()
-		}
-	}
-	if force || !res.Settings.Lock && res.Settings.Cols != "" {
-		res.SettingsCache.Set(res.Sig, &res.Settings)
-	}
-	return
-}
-
-func csvWriter(res *csv.Resource, heads []string) func(map[string]string) {
-	var heading bool
-	if heads == nil {
-		heads = res.Heads
-	}
-	return func(row map[string]string) {
-		if !heading {
-			fmt.Println(`"` + strings.Join(heads, `","`) + `"`)
-			heading = true
-		}
-		var col []string
-		for _, h := range heads {
-			col = append(col, strings.Replace(row[h], `"`, `""`, -1))
-		}
-		fmt.Println(`"` + strings.Join(col, `","`) + `"`)
-	}
-}
-
-func getRes(scache *csv.Settings, fn string) {
-	defer func() {
-		if e := recover(); e != nil {
-			fmt.Printf("%v\n", e)
-		}
-		wg.Done()
-	}()
-	var (
-		r              io.ReadCloser
-		decoder        tel.Decoder
-		rater          tel.Rater
-		write          func(map[string]string)
-		tn             tel.E164full
-		rfmt, currency string
-	)
-	if fn == "" {
-		fn, r = "<stdin>", os.Stdin
-	}
-
-	res, rows := csv.Resource{Location: fn, Comment: "#", Shebang: "#!", SettingsCache: scache}, 0
-	if e := res.Open(r); e != nil {
-		panic(fmt.Errorf("error opening %q: %v", fn, e))
-	}
-	defer res.Close()
-	if csvFlag {
-		write = csvWriter(&res, nil)
-	} else if debugFlag {
-		fmt.Println(res)
-	} else if rateFlag {
-		switch rfmt = res.Settings.Format; rfmt {
-		case "Intelepeer CDR":
-			rater.Default = tel.T1intlTermRates
-			currency, write = "USD", csvWriter(&res, []string{
-				"Call Date",
-				"Call Time",
-				"Customer Account ID",
-				"To Country Code",
-				"Terminating Phone Number",
-				"Route",
-				"Billable Time",
-				"Rate Per Minute",
-				"Billable Amount",
-				"Call Type",
-				"Unique CDR ID",
-				"Re-rated Amount",
-			})
-		case "Alvaria CDR":
-			rater.Default = tel.T2intlTermRates
-			currency, write = "EUR", csvWriter(&res, []string{
-				"gatewayAccountingId",
-				"startTime",
-				"endTime",
-				"fromNumber",
-				"toNumber",
-				"ISO3166",
-				"callDirection",
-				"rawDuration",
-				"meteredDuration",
-				"charges",
-				"reratedCharges",
-			})
-		default:
-			currency, write = "USD", csvWriter(&res, nil)
-		}
-		if e := decoder.Load(nil); e != nil {
-			panic(e)
-		} else if e := rater.Load(nil); e != nil {
-			panic(e)
-		}
-	}
-	res.Cols = updateSettings(&res, colsFlag, forceFlag)
-	in, err := res.Get()
-
-	filtered, failed, charged, rated, ch, ra := 0, 0, 0.0, 0.0, 0.0, 0.0
-	for row := range in {
-		if rows++; csvFlag {
-			write(row)
-		} else if debugFlag {
-			fmt.Println(row)
-		} else if rateFlag {
-			switch filtered++; rfmt {
-			case "Intelepeer CDR":
-				if row["Call Type"] != "" {
-					break
-				} else if err := decoder.Full(row["To Country Code"]+row["Terminating Phone Number"], &tn); err != nil {
-					failed++
-					continue
-				}
-				d, _ := strconv.ParseFloat(row["Billable Time"], 64)
-				ch, _ = strconv.ParseFloat(row["Billable Amount"], 64)
-				if ra = float64(rater.Lookup(&tn)) * d; ra == 0 {
-					ra = ch
-				} else {
-					ra = math.Trunc(ra*1e4+0.999999999) / 1e4
-				}
-				rated += ra
-				charged += ch
-				row["Re-rated Amount"] = fmt.Sprintf("%.4f", ra)
-			case "Alvaria CDR":
-				if row["callDirection"] != "PSTN_OUTBOUND" {
-					break
-				}
-				decoder.Full(row["toNumber"], &tn)
-				d, _ := strconv.ParseFloat(row["rawDuration"], 64)
-				d /= 60000
-				m, _ := strconv.ParseFloat(row["meteredDuration"], 64)
-				m /= 60000
-				ch, _ := strconv.ParseFloat(row["charges"], 64)
-				if ch/m < 0.00251 {
-					ra = 0 // zero-rate presumed BYOC call
-				} else if ra = float64(rater.Lookup(&tn)) * d; ra == 0 {
-					ra = ch
-				} else {
-					ra = math.Trunc(ra*0.86*1e3+0.999999999) / 1e3 // convert USD to EUR
-				}
-				charged += ch
-				rated += ra
-				row["ISO3166"], row["reratedCharges"] = tn.ISO3166, fmt.Sprintf("%.3f", ra)
-			}
-			write(row)
-		}
-	}
-	if e := <-err; e != nil {
-		panic(fmt.Errorf("error reading %q: %v", fn, e))
-	} else if rateFlag {
-		fmt.Printf("filtered %d %q records (%d failed); %.2f%v charged -- rerated to %.2f%v\n",
-			filtered, rfmt, failed, charged, currency, rated, currency)
-	} else if !csvFlag {
-		fmt.Printf("read %d rows from [%s %s] resource at %q\n", rows, res.Settings.Format, res.Settings.Ver, fn)
-	}
-}
-
-func main() {
-	flag.Parse()
-	settings := csv.Settings{Location: settingsFlag}
-	defer settings.Sync()
-	settings.Cache(nil)
-
-	if len(flag.Args()) > 0 {
-		for _, arg := range flag.Args() {
-			files, _ := filepath.Glob(arg)
-			if len(files) == 0 {
-				files = []string{arg}
-			}
-			for _, file := range files {
-				wg.Add(1)
-				go getRes(&settings, file)
-			}
-		}
-	} else {
-		wg.Add(1)
-		go getRes(&settings, "")
-	}
-	wg.Wait()
-}
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/tel"
+)
+
+var (
+	settingsFlag string
+	csvFlag      bool
+	debugFlag    bool
+	rateFlag     bool
+	typedFlag    bool
+	errFlag      bool
+	errLimFlag   int
+	forceFlag    bool
+	colsFlag     string
+	sheetFlag    string
+	regFlag      bool
+	inferFlag    bool
+	ckptFlag     string
+	workersFlag  int
+	unordFlag    bool
+	unionFlag    bool
+	wg           sync.WaitGroup
+
+	ckpts struct {
+		sync.Mutex
+		m map[string]csv.Checkpoint
+	}
+)
+
+func init() {
+	// set up command-line flags
+	flag.StringVar(&settingsFlag, "s", "~/.csv_settings.json", fmt.Sprintf("file-type settings `file` containing column filter maps"))
+	flag.BoolVar(&forceFlag, "f", false, fmt.Sprintf("force file-type settings to settings file"))
+	flag.BoolVar(&regFlag, "reg", false, fmt.Sprintf("register file-type as new version of nearest matching file-type in settings file"))
+	flag.BoolVar(&inferFlag, "infer", false, fmt.Sprintf("propose fixed-field column map and specifier (saved if unset with -f)"))
+	flag.StringVar(&ckptFlag, "ckpt", "", fmt.Sprintf("checkpoint `file` for resuming interrupted reads (saved on interrupt)"))
+	flag.IntVar(&workersFlag, "w", 0, fmt.Sprintf("parallel parse `workers` for uncompressed CSV files (serial default)"))
+	flag.BoolVar(&unordFlag, "u", false, fmt.Sprintf("deliver parallel parsed rows unordered"))
+	flag.BoolVar(&unionFlag, "union", false, fmt.Sprintf("read all files as one resource set of compatible formats (union of columns)"))
+	flag.BoolVar(&csvFlag, "c", false, fmt.Sprintf("specify CSV output"))
+	flag.BoolVar(&debugFlag, "d", false, fmt.Sprintf("specify debug output"))
+	flag.BoolVar(&rateFlag, "r", false, fmt.Sprintf("specify call rating output"))
+	flag.BoolVar(&typedFlag, "t", false, fmt.Sprintf("specify typed-row validation output (using settings column types)"))
+	flag.BoolVar(&errFlag, "e", false, fmt.Sprintf("report malformed rows (to stderr)"))
+	flag.IntVar(&errLimFlag, "elim", -1, fmt.Sprintf("report malformed rows, aborting beyond `limit`"))
+	flag.StringVar(&sheetFlag, "sheet", "", fmt.Sprintf("XLSX worksheet `name` or 1-based index (first default)"))
+	flag.StringVar(&colsFlag, "cols", "", fmt.Sprintf("column filter `map`: "+
+		"'[!]<head>[:<filter>]...[[:<bcol>]:<col>][,...]'  (filters: '(=|!){<pfx>[:<pfx>]...}', '[!]~{<regex>}', '#{[<lo>]..[<hi>]}';\n"+
+		"ex. 'name,,!stat:={OK},age:#{18..},acct:!{n/a:0000}:6');\n"+
+		"derived columns: '[!]<head>:@{<expr>}[:<filter>]...'  (ex. 'total:@{round([qty]*[rate],2)}', 'name:@{cat([last],\", \",[first])}')"))
+
+	// call on ErrHelp
+	flag.Usage = func() {
+		fmt.Printf("command usage: csv [-c] [-d] [-f] [-reg] [-infer] [-t] [-e] [-elim <limit>] [-cols '<map>'] [-sheet <name>] [-w <workers> [-u]] [-ckpt <file>] [-union] [-s <file>] <csvfile> [...]" +
+			"\n\nThis command identifies and parses CSV, fixed-field TXT, JSON Lines, XLSX and Parquet files using column filter maps.\n\n")
+		flag.PrintDefaults()
+	}
+}
+
+func inferSettings(res *csv.Resource, cflag string, force bool) {
+	cols, spec, e := res.InferCols()
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "cannot infer columns for %q: %v\n", res.Location, e)
+		return
+	}
+	fmt.Printf("proposed column map: '%s'\nproposed specifier: '%s'\n", cols, spec)
+	if cflag == "" && res.Settings.Cols == "" && force {
+		res.Settings.Cols, res.Settings.Date = cols, time.Now()
+	}
+	if res.Sig == "" && force {
+		res.Sig, res.Settings.Date = spec, time.Now()
+	}
+}
+
+func updateSettings(res *csv.Resource, cflag string, force bool) (cols string) {
+	if inferFlag && res.Typ == csv.RTfixed {
+		inferSettings(res, cflag, force)
+	}
+	switch cflag {
+	case "":
+		cols = res.Settings.Cols
+	case "*":
+	default:
+		if cols = cflag; res.Settings.Cols != cols {
+			res.Settings.Cols, res.Settings.Date = cols, time.Now()
+		}
+	}
+
+	switch res.Typ { // these initial settings should be manually updated
+	case csv.RTcsv, csv.RTxlsx:
+		if res.Sig == "" && force {
+			res.Sig, res.Settings.Date = fmt.Sprintf("=%s%d", string(res.Sep), len(res.Split[0])), time.Now()
+		}
+		if res.Settings.Format == "" && res.Settings.Ver == "" {
+			res.Settings.Format, res.Settings.Ver, res.Settings.Date = "unspecified CSV", res.Location, time.Now()
+		}
+	case csv.RTfixed:
+		if res.Sig == "" && force {
+			res.Sig, res.Settings.Date = fmt.Sprintf("=h%d,f%d", len(res.Preview[0]), len(res.Preview[1])), time.Now()
+		}
+		if res.Settings.Format == "" && res.Settings.Ver == "" {
+			res.Settings.Format, res.Settings.Ver, res.Settings.Date = "unspecified fixed-field", res.Location, time.Now()
+		}
+	case csv.RTjsonl:
+		if res.Settings.Format == "" && res.Settings.Ver == "" {
+			res.Settings.Format, res.Settings.Ver, res.Settings.Date = "unspecified JSON Lines", res.Location, time.Now()
+		}
+	case csv.RTparquet:
+		if res.Settings.Format == "" && res.Settings.Ver == "" {
+			res.Settings.Format, res.Settings.Ver, res.Settings.Date = "unspecified Parquet", res.Location, time.Now()
+		}
+	}
+	if res.Encoding != "utf-8" && res.Settings.Encoding != res.Encoding {
+		res.Settings.Encoding, res.Settings.Date = res.Encoding, time.Now()
+	}
+	switch {
+	case res.Nearest != nil && res.Nearest.Applied && regFlag:
+		if e := res.Register(res.Location); e != nil {
+			panic(fmt.Errorf("error registering %q: %v", res.Location, e))
+		}
+	case res.Nearest != nil && res.Nearest.Applied && !force:
+		// nearest file-type settings applied but not registered
+	case force || !res.Settings.Lock && res.Settings.Cols != "":
+		if h := res.Columns(); len(h) > 0 && len(res.Settings.Head) == 0 {
+			res.Settings.Head = h
+		}
+		res.SettingsCache.Set(res.Sig, &res.Settings)
+	}
+	return
+}
+
+func reportNearest(res *csv.Resource) {
+	if m := res.Nearest; m != nil {
+		s := res.SettingsCache.Get(m.Sig)
+		fmt.Printf("nearest file-type [%s %s] is %.0f%% similar (added %q; removed %q)", s.Format, s.Ver, m.Score*100, m.Added, m.Removed)
+		if m.Applied {
+			fmt.Printf(": settings applied\n")
+		} else {
+			fmt.Printf("\n")
+		}
+	}
+}
+
+func csvWriter(res *csv.Resource, heads []string) func(map[string]string) {
+	var wr *csv.Writer
+	return func(row map[string]string) {
+		if wr == nil {
+			if heads == nil {
+				heads = res.Heads
+			}
+			wr = &csv.Writer{Heads: heads, Heading: true, Quote: true}
+			if e := wr.Open(os.Stdout); e != nil {
+				panic(e)
+			}
+		}
+		if e := wr.Put(row); e != nil {
+			panic(e)
+		}
+	}
+}
+
+func getRes(scache *csv.Settings, fn string) {
+	defer func() {
+		if e := recover(); e != nil {
+			fmt.Printf("%v\n", e)
+		}
+		wg.Done()
+	}()
+	var (
+		r              io.ReadCloser
+		decoder        tel.Decoder
+		rater          tel.Rater
+		write          func(map[string]string)
+		tn             tel.E164full
+		rfmt, currency string
+	)
+	if fn == "" {
+		fn, r = "<stdin>", os.Stdin
+	}
+
+	res, rows := csv.Resource{Location: fn, Comment: "#", Shebang: "#!", Sheet: sheetFlag, SettingsCache: scache,
+		Workers: workersFlag, Unordered: unordFlag, Checkpoints: ckptFlag != ""}, 0
+	if regFlag {
+		res.MatchMin = 0.8 // nearest file-type settings applied for registration
+	}
+	if ck, ok := getCheckpoint(fn); ok {
+		res.Resume = &ck
+	}
+	if e := res.Open(r); e != nil {
+		panic(fmt.Errorf("error opening %q: %v", fn, e))
+	}
+	defer res.Close()
+	if csvFlag {
+		write = csvWriter(&res, nil)
+	} else if debugFlag {
+		fmt.Println(res)
+	} else if rateFlag {
+		switch rfmt = res.Settings.Format; rfmt {
+		case "Intelepeer CDR":
+			rater.Default = tel.T1intlTermRates
+			currency, write = "USD", csvWriter(&res, []string{
+				"Call Date",
+				"Call Time",
+				"Customer Account ID",
+				"To Country Code",
+				"Terminating Phone Number",
+				"Route",
+				"Billable Time",
+				"Rate Per Minute",
+				"Billable Amount",
+				"Call Type",
+				"Unique CDR ID",
+				"Re-rated Amount",
+			})
+		case "Alvaria CDR":
+			rater.Default = tel.T2intlTermRates
+			currency, write = "EUR", csvWriter(&res, []string{
+				"gatewayAccountingId",
+				"startTime",
+				"endTime",
+				"fromNumber",
+				"toNumber",
+				"ISO3166",
+				"callDirection",
+				"rawDuration",
+				"meteredDuration",
+				"charges",
+				"reratedCharges",
+			})
+		default:
+			currency, write = "USD", csvWriter(&res, nil)
+		}
+		if e := decoder.Load(nil); e != nil {
+			panic(e)
+		} else if e := rater.Load(nil); e != nil {
+			panic(e)
+		}
+	}
+	res.Cols = updateSettings(&res, colsFlag, forceFlag)
+	switch {
+	case errLimFlag >= 0:
+		res.ErrPolicy, res.ErrLimit = csv.EPabort, errLimFlag
+	case errFlag:
+		res.ErrPolicy = csv.EPcollect
+	}
+	if typedFlag {
+		validateRes(&res, fn)
+		return
+	}
+	in, err := res.Get()
+	defer reportErrs(&res, fn)()
+
+	filtered, failed, charged, rated, ch, ra := 0, 0, 0.0, 0.0, 0.0, 0.0
+	checkpoint := func(row map[string]string) { // after each row is handled (including skipped rows)
+		if ckptFlag != "" {
+			setCheckpoint(fn, res.Checkpoint(row))
+		}
+	}
+	for row := range in {
+		if rows++; csvFlag {
+			write(row)
+		} else if debugFlag {
+			fmt.Println(row)
+		} else if rateFlag {
+			switch filtered++; rfmt {
+			case "Intelepeer CDR":
+				if row["Call Type"] != "" {
+					break
+				} else if err := decoder.Full(row["To Country Code"]+row["Terminating Phone Number"], &tn); err != nil {
+					failed++
+					checkpoint(row)
+					continue
+				}
+				d, _ := strconv.ParseFloat(row["Billable Time"], 64)
+				ch, _ = strconv.ParseFloat(row["Billable Amount"], 64)
+				if ra = float64(rater.Lookup(&tn)) * d; ra == 0 {
+					ra = ch
+				} else {
+					ra = math.Trunc(ra*1e4+0.999999999) / 1e4
+				}
+				rated += ra
+				charged += ch
+				row["Re-rated Amount"] = fmt.Sprintf("%.4f", ra)
+			case "Alvaria CDR":
+				if row["callDirection"] != "PSTN_OUTBOUND" {
+					break
+				}
+				decoder.Full(row["toNumber"], &tn)
+				d, _ := strconv.ParseFloat(row["rawDuration"], 64)
+				d /= 60000
+				m, _ := strconv.ParseFloat(row["meteredDuration"], 64)
+				m /= 60000
+				ch, _ := strconv.ParseFloat(row["charges"], 64)
+				if ch/m < 0.00251 {
+					ra = 0 // zero-rate presumed BYOC call
+				} else if ra = float64(rater.Lookup(&tn)) * d; ra == 0 {
+					ra = ch
+				} else {
+					ra = math.Trunc(ra*0.86*1e3+0.999999999) / 1e3 // convert USD to EUR
+				}
+				charged += ch
+				rated += ra
+				row["ISO3166"], row["reratedCharges"] = tn.ISO3166, fmt.Sprintf("%.3f", ra)
+			}
+			write(row)
+		}
+		checkpoint(row)
+	}
+	if e := <-err; e != nil {
+		panic(fmt.Errorf("error reading %q: %v", fn, e))
+	} else if clearCheckpoint(fn); rateFlag {
+		fmt.Printf("filtered %d %q records (%d failed); %.2f%v charged -- rerated to %.2f%v\n",
+			filtered, rfmt, failed, charged, currency, rated, currency)
+	} else if !csvFlag {
+		reportNearest(&res)
+		fmt.Printf("read %d rows from [%s %s] resource at %q\n", rows, res.Settings.Format, res.Settings.Ver, fn)
+	}
+}
+
+func loadCheckpoints() {
+	ckpts.m = make(map[string]csv.Checkpoint)
+	if b, e := ioutil.ReadFile(ckptFlag); e == nil {
+		if e = json.Unmarshal(b, &ckpts.m); e != nil {
+			fmt.Fprintf(os.Stderr, "ignoring checkpoint file %q: %v\n", ckptFlag, e)
+		}
+	}
+}
+
+func getCheckpoint(fn string) (ck csv.Checkpoint, ok bool) {
+	if ckptFlag == "" {
+		return
+	}
+	defer ckpts.Unlock()
+	ckpts.Lock()
+	ck, ok = ckpts.m[fn]
+	return
+}
+
+func setCheckpoint(fn string, ck csv.Checkpoint) {
+	defer ckpts.Unlock()
+	ckpts.Lock()
+	ckpts.m[fn] = ck
+}
+
+func clearCheckpoint(fn string) {
+	if ckptFlag == "" {
+		return
+	}
+	defer ckpts.Unlock()
+	ckpts.Lock()
+	delete(ckpts.m, fn)
+}
+
+func saveCheckpoints() {
+	if ckptFlag == "" {
+		return
+	}
+	defer ckpts.Unlock()
+	ckpts.Lock()
+	if len(ckpts.m) == 0 {
+		os.Remove(ckptFlag)
+	} else if b, e := json.MarshalIndent(ckpts.m, "", "\t"); e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+	} else if e = ioutil.WriteFile(ckptFlag, b, 0644); e != nil {
+		fmt.Fprintf(os.Stderr, "cannot save checkpoints: %v\n", e)
+	}
+}
+
+func getSet(scache *csv.Settings, locs []string) {
+	defer func() {
+		if e := recover(); e != nil {
+			fmt.Printf("%v\n", e)
+		}
+	}()
+	set := csv.ResourceSet{Locations: locs, Template: csv.Resource{Comment: "#", Shebang: "#!", Sheet: sheetFlag,
+		SettingsCache: scache, Workers: workersFlag}}
+	if colsFlag != "*" {
+		set.Template.Cols = colsFlag
+	}
+	switch {
+	case errLimFlag >= 0:
+		set.Template.ErrPolicy, set.Template.ErrLimit = csv.EPabort, errLimFlag
+	case errFlag:
+		set.Template.ErrPolicy = csv.EPcollect
+	}
+	if e := set.Open(); e != nil {
+		panic(fmt.Errorf("error opening resource set: %v", e))
+	}
+	defer set.Close()
+
+	var wr *csv.Writer
+	if csvFlag {
+		wr = &csv.Writer{Heads: append(append([]string{}, set.Heads...), "~file"), Heading: true, Quote: true}
+		if e := wr.Open(os.Stdout); e != nil {
+			panic(e)
+		}
+	}
+	in, err := set.Get()
+	var ewg sync.WaitGroup
+	ewg.Add(1)
+	go func() {
+		defer ewg.Done()
+		for e := range set.RowErrs() {
+			fmt.Fprintf(os.Stderr, "%q %v (%q)\n", e.File, e, e.Raw)
+		}
+	}()
+	defer ewg.Wait()
+
+	rows := 0
+	for row := range in {
+		if rows++; csvFlag {
+			if e := wr.Put(row); e != nil {
+				panic(e)
+			}
+		} else if debugFlag {
+			fmt.Println(row)
+		}
+	}
+	if e := <-err; e != nil {
+		panic(e)
+	} else if !csvFlag {
+		fmt.Printf("read %d rows from %d [%s] resources (%d columns)\n", rows, len(set.Members), set.Format, len(set.Heads))
+	}
+}
+
+func reportErrs(res *csv.Resource, fn string) (wait func()) {
+	var ewg sync.WaitGroup
+	ewg.Add(1)
+	go func() {
+		defer ewg.Done()
+		for e := range res.RowErrs() {
+			fmt.Fprintf(os.Stderr, "%q %v (%q)\n", fn, e, e.Raw)
+		}
+	}()
+	return ewg.Wait
+}
+
+func validateRes(res *csv.Resource, fn string) {
+	in, err := res.GetTyped()
+	defer reportErrs(res, fn)()
+	rows, invalid := 0, 0
+	for row := range in {
+		if rows++; len(row.Errs) > 0 {
+			invalid++
+			for _, e := range row.Errs {
+				fmt.Printf("%q %v (%q)\n", fn, e, e.Raw)
+			}
+		}
+	}
+	if e := <-err; e != nil {
+		panic(fmt.Errorf("error reading %q: %v", fn, e))
+	}
+	fmt.Printf("validated %d rows (%d invalid) from [%s %s] resource at %q\n", rows, invalid, res.Settings.Format, res.Settings.Ver, fn)
+}
+
+func main() {
+	flag.Parse()
+	if ckptFlag != "" && unordFlag {
+		fmt.Fprintf(os.Stderr, "-ckpt cannot be used with -u (unordered rows have no resumable position)\n")
+		os.Exit(1)
+	}
+	settings := csv.Settings{Location: settingsFlag}
+	defer func() {
+		if e := settings.Sync(); e != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", e)
+		}
+	}()
+	settings.Cache(nil)
+	if ckptFlag != "" {
+		loadCheckpoints()
+		defer saveCheckpoints()
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		go func() {
+			<-sig
+			saveCheckpoints()
+			if e := settings.Sync(); e != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", e)
+			}
+			os.Exit(130)
+		}()
+	}
+
+	if unionFlag && len(flag.Args()) > 0 {
+		getSet(&settings, flag.Args())
+	} else if len(flag.Args()) > 0 {
+		for _, arg := range flag.Args() {
+			files, _ := filepath.Glob(arg)
+			if len(files) == 0 {
+				files = []string{arg}
+			}
+			for _, file := range files {
+				wg.Add(1)
+				go getRes(&settings, file)
+			}
+		}
+	} else {
+		wg.Add(1)
+		go getRes(&settings, "")
+	}
+	wg.Wait()
+}
@@ -1,275 +1,832 @@
-package csv
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"io/ioutil"
-	"os"
-	"strings"
-	"sync"
-	"time"
-
-	iio "github.com/sententico/cost/internal/io"
-)
-
-type (
-	// ResTyp specifies a Resource type
-	ResTyp uint8
-
-	// Resource contains data and metadata for supported package types (CSV, fixed-field, ...)
-	Resource struct {
-		Location      string    // resource location (pathname, ...)
-		Typ           ResTyp    // resource type
-		Cols          string    // resource column map
-		Comment       string    // comment line prefix
-		Shebang       string    // metadata line prefix (Comment + "!" default)
-		Sep           rune      // field separator rune (for CSV resources)
-		SettingsCache *Settings // format Settings resource cache
-
-		Preview  []string     // preview rows (excluding blank & comment lines)
-		Split    [][]string   // trimmed fields of Preview rows split by Sep (if CSV)
-		Heads    []string     // column heads from column map
-		Heading  bool         // first row is a heading
-		Rows     int          // estimated total resource rows (-1 if unknown)
-		Sig      string       // format signature (specifier or heading MD5 hash, if determined)
-		Settings SettingsItem // format settings matched to Sig in SettingsCache (if found)
-
-		stat     resStat
-		reader   io.ReadCloser
-		finfo    os.FileInfo
-		peek, in <-chan string
-		ierr     <-chan error
-		isig     chan<- int
-		out      chan map[string]string
-		err      chan error
-		sig      chan int
-	}
-
-	// SettingsItem contains Resource format information & settings retrieved by its signature
-	// (specifier or heading MD5 hash) from the Settings cache
-	SettingsItem struct {
-		Cols   string    // column map default
-		Format string    // format name
-		Ver    string    // format version
-		Date   time.Time // entry update timestamp
-		Lock   bool      // entry locked to automatic updates
-	}
-
-	// Settings cache maps format settings by Resource signature (specifier or heading MD5 hash)
-	Settings struct {
-		Location string     // settings location (pathname, ...)
-		writable bool       // true if cache is writable
-		mutex    sync.Mutex // mutex to allow concurrent cache access
-		cache    map[string]SettingsItem
-	}
-)
-
-// Resource type constants
-const (
-	RTunk   ResTyp = iota // unknown/indeterminate content
-	RTempty               // unknown/no content
-	RTcsv                 // CSV
-	RTfixed               // fixed-field
-)
-
-// Open method on Resource populates resource fields for identification and prepares resource
-// for Get method extraction. If a SettingsCache is specified, known resource formats can be
-// automatically be identified in Settings.
-func (res *Resource) Open(r io.ReadCloser) (e error) {
-	switch res.stat {
-	case rsOPEN, rsGET:
-		return fmt.Errorf("resource already open")
-	case rsCLOSED:
-		return fmt.Errorf("resource must be uninitialized")
-	}
-	defer func() {
-		if i := recover(); i != nil {
-			e = i.(error)
-			if res.reader != nil {
-				res.reader.Close()
-				res.reader = nil
-			}
-			if res.isig != nil {
-				close(res.isig)
-				res.isig = nil
-			}
-		}
-	}()
-
-	if r == nil {
-		if f, e := os.Open(iio.ResolveName(res.Location)); e != nil {
-			panic(e)
-		} else if res.finfo, e = f.Stat(); e != nil {
-			f.Close()
-			panic(e)
-		} else {
-			r = f
-		}
-	}
-	res.reader = r
-
-	res.peek, res.in, res.ierr, res.isig = iio.ReadLn(r, previewLines)
-	res.peekAhead()
-	res.stat = rsOPEN
-	return nil
-}
-
-// Get method on Resource returns a receive channel over which the consumer may iterate and an
-// error channel which should be checked once receive channel is closed. Key-value maps are
-// returned as specified in Cols.
-func (res *Resource) Get() (<-chan map[string]string, <-chan error) {
-	switch res.stat {
-	case rsNIL, rsCLOSED:
-		out, err := make(chan map[string]string, 1), make(chan error, 1)
-		err <- fmt.Errorf("resource not open")
-		close(err)
-		close(out)
-		return out, err
-	case rsGET:
-		return res.out, res.err
-	}
-
-	res.stat, res.Heads = rsGET, res.getHeads()
-	res.out, res.err, res.sig = make(chan map[string]string, 64), make(chan error, 1), make(chan int)
-	go func() {
-		defer func() {
-			if e := recover(); e != nil {
-				res.err <- e.(error)
-			}
-			close(res.isig)
-			close(res.err)
-			close(res.out)
-		}()
-
-		switch res.Typ {
-		case RTcsv:
-			res.getCSV()
-		case RTfixed:
-			res.getFixed()
-		case RTempty:
-		default:
-			panic(fmt.Errorf("unknown resource type"))
-		}
-		if e := <-res.ierr; e != nil {
-			panic(fmt.Errorf("problem reading resource (%v)", e))
-		}
-	}()
-	return res.out, res.err
-}
-
-// Close method on Resource closes resource and signals termination of upstream flow; resource
-// may not be re-opened.
-func (res *Resource) Close() error {
-	switch res.stat {
-	case rsNIL, rsCLOSED:
-		return fmt.Errorf("resource not open")
-	case rsOPEN:
-		close(res.isig)
-	case rsGET:
-		close(res.sig)
-	}
-	res.reader.Close()
-	res.stat = rsCLOSED
-	return nil
-}
-
-// Cache method on Settings reads JSON-encoded format settings resource into cache.
-func (set *Settings) Cache(r io.Reader) {
-	if set == nil {
-		return
-	}
-	defer set.mutex.Unlock()
-	if set.mutex.Lock(); r == nil {
-		switch file, err := os.Open(iio.ResolveName(set.Location)); err {
-		case nil:
-			defer file.Close()
-			r = file
-		default:
-			set.cache = make(map[string]SettingsItem)
-			set.writable = os.IsNotExist(err)
-			return
-		}
-	}
-
-	switch b, err := io.ReadAll(r); err {
-	case nil:
-		if err = json.Unmarshal(b, &set.cache); err != nil {
-			set.cache = make(map[string]SettingsItem)
-		}
-		set.writable = err == nil
-	default:
-		set.cache = make(map[string]SettingsItem)
-	}
-}
-
-// Sync method on Settings writes (potentially modified) cached format settings back to the JSON
-// settings resource; this implementation does not properly sync (file overwrite only).
-func (set *Settings) Sync() error {
-	if set == nil || set.cache == nil || set.Location == "" {
-		return fmt.Errorf("can't write settings cache")
-	} else if !set.writable {
-		return fmt.Errorf("can't write settings cache to %q", set.Location)
-	}
-	defer set.mutex.Unlock()
-	set.mutex.Lock()
-
-	b, err := json.MarshalIndent(set.cache, "", "\t")
-	if err != nil {
-		return fmt.Errorf("can't write settings cache to %q (%v)", set.Location, err)
-	}
-	return ioutil.WriteFile(iio.ResolveName(set.Location), b, 0644)
-}
-
-// Find method on Settings returns true if format signature exists in the cache.
-func (set *Settings) Find(sig string) (found bool) {
-	if set == nil || set.cache == nil || sig == "" {
-		return
-	}
-	defer set.mutex.Unlock()
-	set.mutex.Lock()
-
-	_, found = set.cache[sig]
-	return
-}
-
-// Get method on Settings returns cache item located by format signature.
-func (set *Settings) Get(sig string) (i SettingsItem) {
-	if set == nil || set.cache == nil || sig == "" {
-		return
-	}
-	defer set.mutex.Unlock()
-	set.mutex.Lock()
-
-	i, _ = set.cache[sig]
-	return
-}
-
-// GetSpecs method on Settings returns list of format specifications in the cache.
-func (set *Settings) GetSpecs() (specs []string) {
-	if set == nil || set.cache == nil {
-		return
-	}
-	defer set.mutex.Unlock()
-	set.mutex.Lock()
-
-	for sig := range set.cache {
-		if strings.HasPrefix(sig, "=") && len(sig) > 2 {
-			specs = append(specs, sig)
-		}
-	}
-	return
-}
-
-// Set method on Settings returns item set (added or updated) in cache indexed under format
-// signature.
-func (set *Settings) Set(sig string, item *SettingsItem) *SettingsItem {
-	if set == nil || set.cache == nil || sig == "" {
-		return nil
-	}
-	defer set.mutex.Unlock()
-	set.mutex.Lock()
-
-	set.cache[sig] = *item
-	return item
-}
+package csv
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	iio "github.com/sententico/cost/internal/io"
+)
+
+type (
+	// ResTyp specifies a Resource type
+	ResTyp uint8
+
+	// ErrPolicy specifies Resource malformed row handling
+	ErrPolicy uint8
+
+	// Resource contains data and metadata for supported package types (CSV, fixed-field, JSON
+	// Lines, ...); nested JSON object values are keyed by dotted paths (arrays by element index)
+	Resource struct {
+		Location      string      // resource location (pathname, ...)
+		Typ           ResTyp      // resource type
+		Cols          string      // resource column map
+		Comment       string      // comment line prefix
+		Shebang       string      // metadata line prefix (Comment + "!" default)
+		Sep           rune        // field separator rune (for CSV resources)
+		Sheet         string      // worksheet name or 1-based index (for XLSX resources; first default)
+		Codec         string      // compression codec of resource content ("gzip", "bzip2", "zstd", "snappy" or "")
+		Encoding      string      // character encoding of resource content (detected if unspecified)
+		Types         ColTypes    // column type schema for GetTyped (Settings default)
+		ErrPolicy     ErrPolicy   // malformed row handling policy (see RowErrs)
+		ErrLimit      int         // malformed row limit before abort (EPabort policy)
+		MatchMin      float64     // minimum nearest-format score to apply its Settings (never if 0 or >1)
+		Resume        *Checkpoint // Get resumes following checkpoint (if specified on Open)
+		Checkpoints   bool        // Get rows carry content offsets ("~off", "~lines") for Checkpoint
+		Workers       int         // parallel Get parse workers for uncompressed UTF-8 CSV files (serial if <2)
+		Unordered     bool        // parallel Get rows delivered as parsed, not in source order (unless Checkpoints)
+		SettingsCache *Settings   // format Settings resource cache
+
+		Preview  []string     // preview rows (excluding blank & comment lines)
+		Split    [][]string   // trimmed fields of Preview rows split by Sep (if CSV; by key if JSON)
+		Heads    []string     // column heads from column map
+		Heading  bool         // first row is a heading
+		Rows     int          // estimated total resource rows (-1 if unknown)
+		Sig      string       // format signature (specifier or heading MD5 hash, if determined)
+		Settings SettingsItem // format settings matched to Sig in SettingsCache (if found)
+		Nearest  *FormatMatch // nearest known format in SettingsCache (if Sig not found)
+
+		stat   resStat
+		reader io.ReadCloser
+		finfo  os.FileInfo
+		peek   <-chan string
+		in     <-chan iio.Rec
+		ierr   <-chan error
+		isig   chan<- int
+		out    chan map[string]string
+		err    chan error
+		sig    chan int
+		rerr   chan *RowError
+		nerr   int
+		jkeys  []string
+		lines  bool // records read as unjoined lines (JSON Lines)
+		pq     *pqFile
+		der    []dcol
+		file   *os.File
+	}
+
+	// ResourceSet contains member Resources read as one dataset (such as a month of daily files):
+	// members are opened from pathnames or glob patterns like Template, checked for compatible
+	// formats, and their rows streamed through a single Get (re-opening each member only as it is
+	// read) with each row's member Location keyed by "~file"
+	ResourceSet struct {
+		Locations []string    // member pathnames or glob patterns (matches read in lexical order)
+		Template  Resource    // member template (Cols, Comment, SettingsCache, ...; Location, Resume, Checkpoints ignored)
+		Members   []*Resource // members (as detected on Open, then closed until read by Get)
+		Heads     []string    // union of member column heads (in order of first appearance)
+		Format    string      // common member format (Settings Format, or signature if unknown)
+
+		stat resStat
+		out  chan map[string]string
+		err  chan error
+		sig  chan int
+		rerr chan *RowError
+	}
+
+	// ColTypes maps column heads to type specifiers for typed row conversion:
+	//   "int" (int64), "dec" (float64), "time[:<layout>]" (time.Time; RFC 3339 default),
+	//   "e164" (E.164 digit string), "enum:<val>[|<val>]..." (string), "str" (string default)
+	ColTypes map[string]string
+
+	// TypedRow contains a Resource row with values converted as specified by its column type schema
+	TypedRow struct {
+		Line int                    // source line number
+		Vals map[string]interface{} // converted column values (omitted if blank or invalid)
+		Errs []*RowError            // column conversion errors
+	}
+
+	// RowError describes a Resource row (or column) problem
+	RowError struct {
+		Line   int    // source line number
+		Off    int64  // source byte offset of row (if known)
+		Col    string // column head (if column-specific)
+		Raw    string // raw row (or column) text
+		Reason string // problem description
+		File   string // resource location (ResourceSet member rows)
+	}
+
+	// Writer encodes key-value maps (or field slices) into CSV or fixed-field resources as specified
+	// by a column map (in Resource Cols syntax); each row is written with a single Write call
+	Writer struct {
+		Location string   // writer location (pathname, ...) if no io.Writer provided on Open
+		Typ      ResTyp   // writer type (RTcsv default, or RTfixed)
+		Cols     string   // writer column map (begin/end columns required for fixed-field writers)
+		Heads    []string // column heads in column order (from Cols if specified)
+		Sep      rune     // field separator rune (for CSV writers; ',' default)
+		Heading  bool     // write heading row of column heads on Open
+		Quote    bool     // enclose all CSV fields in double-quotes (only as required by default)
+		Guard    bool     // guard fields against spreadsheet formula interpretation
+
+		w       io.Writer
+		file    *os.File
+		layout  []wcol
+		filters []wcol
+		wid     int
+	}
+
+	// SettingsItem contains Resource format information & settings retrieved by its signature
+	// (specifier or heading MD5 hash) from the Settings cache
+	SettingsItem struct {
+		Cols     string    // column map default
+		Format   string    // format name
+		Ver      string    // format version
+		Encoding string    `json:",omitempty"` // character encoding (UTF-8 default)
+		Types    ColTypes  `json:",omitempty"` // column type schema
+		Head     []string  `json:",omitempty"` // column heads (for nearest-format matching)
+		Date     time.Time // entry update timestamp
+		Lock     bool      // entry locked to automatic updates
+	}
+
+	// Settings cache maps format settings by Resource signature (specifier or heading MD5 hash)
+	Settings struct {
+		Location string     // settings location (pathname, ...)
+		writable bool       // true if cache is writable
+		mutex    sync.Mutex // mutex to allow concurrent cache access
+		cache    map[string]SettingsItem
+		base     map[string]SettingsItem // settings as last read or written (for Sync merge)
+		dirty    map[string]bool         // signatures Set since last Sync
+	}
+
+	// Checkpoint identifies a Resource position following a row returned by Get, from which an
+	// interrupted Get may be resumed on a later Open of the same resource
+	Checkpoint struct {
+		Off  int64  // content byte offset following row (row number for Parquet resources)
+		Line int    // source line number ending row
+		Sig  string // resource format signature
+	}
+
+	// FormatMatch describes the known format nearest a Resource whose signature isn't in Settings
+	FormatMatch struct {
+		Sig     string   // nearest format signature
+		Score   float64  // similarity score (0-1) by column overlap and order
+		Added   []string // column heads in resource but not in format
+		Removed []string // column heads in format but not in resource
+		Applied bool     // format Settings applied to resource (Score >= MatchMin)
+	}
+
+	// SettingsConflict describes format signatures whose concurrent updates Sync could not
+	// resolve (settings on disk were retained for these)
+	SettingsConflict struct {
+		Location string   // settings location
+		Sigs     []string // conflicting format signatures
+	}
+)
+
+// Resource type constants
+const (
+	RTunk     ResTyp = iota // unknown/indeterminate content
+	RTempty                 // unknown/no content
+	RTcsv                   // CSV
+	RTfixed                 // fixed-field
+	RTjsonl                 // JSON Lines (NDJSON)
+	RTxlsx                  // XLSX worksheet (read as CSV)
+	RTparquet               // Parquet
+)
+
+// Resource malformed row handling policy constants
+const (
+	EPskip    ErrPolicy = iota // skip malformed rows (aborting if excessive)
+	EPcollect                  // skip malformed rows, reporting them over RowErrs channel
+	EPabort                    // report malformed rows over RowErrs channel, aborting beyond ErrLimit
+)
+
+// Open method on Resource populates resource fields for identification and prepares resource
+// for Get method extraction. If a SettingsCache is specified, known resource formats can be
+// automatically be identified in Settings. Compressed resource content (gzip, bzip2, zstd) is
+// transparently decompressed and transcoded to UTF-8 from its specified (or detected) Encoding;
+// XLSX workbook content is read from the specified (or first) Sheet as CSV, and Parquet content
+// is read by row group.
+func (res *Resource) Open(r io.ReadCloser) (e error) {
+	switch res.stat {
+	case rsOPEN, rsGET:
+		return fmt.Errorf("resource already open")
+	case rsCLOSED:
+		return fmt.Errorf("resource must be uninitialized")
+	}
+	defer func() {
+		if i := recover(); i != nil {
+			e, res.reader = i.(error), nil
+			if r != nil {
+				r.Close()
+			}
+			if res.isig != nil {
+				close(res.isig)
+				res.isig = nil
+			}
+		}
+	}()
+
+	var file *os.File
+	if r == nil {
+		if f, e := os.Open(iio.ResolveName(res.Location)); e != nil {
+			panic(e)
+		} else if res.finfo, e = f.Stat(); e != nil {
+			f.Close()
+			panic(e)
+		} else {
+			r, file, res.file = f, f, f
+		}
+	}
+	var nr io.ReadCloser
+	if nr, e = res.openXLSX(r); e != nil {
+		panic(e)
+	}
+	r = nr
+	if nr, e = res.openParquet(r); e != nil {
+		panic(e)
+	}
+	r = nr
+	if res.pq != nil {
+		ierr := make(chan error)
+		close(ierr)
+		res.reader, res.ierr, res.isig = r, ierr, make(chan int)
+		if res.peekParquet(); res.Resume != nil {
+			res.resume(nil)
+		}
+		res.stat = rsOPEN
+		return nil
+	}
+	if nr, res.Codec, e = iio.Decompress(r, res.Location); e != nil {
+		panic(e)
+	}
+	r = nr
+	if nr, res.Encoding, e = iio.Decode(r, res.Encoding); e != nil {
+		panic(e)
+	}
+	r = nr
+	res.reader = r
+
+	br := bufio.NewReaderSize(r, 64<<10)
+	res.lines = (res.Typ == RTunk || res.Typ == RTjsonl) && jsonHead(br)
+	res.peek, res.in, res.ierr, res.isig = res.readRec(br, previewLines)
+	if res.peekAhead(); res.Resume != nil {
+		res.resume(file)
+	}
+	res.stat = rsOPEN
+	return nil
+}
+
+// Get method on Resource returns a receive channel over which the consumer may iterate and an
+// error channel which should be checked once receive channel is closed. Key-value maps are
+// returned as specified in Cols.
+func (res *Resource) Get() (<-chan map[string]string, <-chan error) {
+	switch res.stat {
+	case rsNIL, rsCLOSED:
+		out, err := make(chan map[string]string, 1), make(chan error, 1)
+		err <- fmt.Errorf("resource not open")
+		close(err)
+		close(out)
+		return out, err
+	case rsGET:
+		return res.out, res.err
+	}
+
+	res.stat, res.Heads = rsGET, res.getHeads()
+	res.out, res.err, res.sig = make(chan map[string]string, 64), make(chan error, 1), make(chan int)
+	res.rerr = make(chan *RowError, 64)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				res.err <- e.(error)
+			}
+			close(res.isig)
+			close(res.rerr)
+			close(res.err)
+			close(res.out)
+		}()
+
+		switch res.der = parseDerived(res.Cols); res.Typ {
+		case RTcsv, RTxlsx:
+			if res.parallel() {
+				res.getCSVPar()
+			} else {
+				res.getCSV()
+			}
+		case RTfixed:
+			res.getFixed()
+		case RTjsonl:
+			res.getJSONL()
+		case RTparquet:
+			res.getParquet()
+		case RTempty:
+		default:
+			panic(fmt.Errorf("unknown resource type"))
+		}
+		if e := <-res.ierr; e != nil {
+			panic(fmt.Errorf("problem reading resource (%v)", e))
+		}
+	}()
+	return res.out, res.err
+}
+
+// RowErrs method on Resource returns a receive channel (following Get) over which malformed rows
+// are reported with EPcollect or EPabort policies; the consumer must receive concurrently with
+// Get rows, as the channel is closed only when Get is complete.
+func (res *Resource) RowErrs() <-chan *RowError {
+	if res.rerr == nil {
+		rerr := make(chan *RowError)
+		close(rerr)
+		return rerr
+	}
+	return res.rerr
+}
+
+// Checkpoint method on Resource returns the checkpoint following "row" (as returned by Get with
+// Checkpoints requested), which may be persisted and later specified as Resume to continue reading
+// the resource after that row; uncompressed UTF-8 CSV and fixed-field files are re-read from the
+// checkpoint, while other resources are skipped through it.
+func (res *Resource) Checkpoint(row map[string]string) Checkpoint {
+	off, _ := strconv.ParseInt(row["~off"], 10, 64)
+	return Checkpoint{Off: off, Line: atoi(row["~line"], 0) + atoi(row["~lines"], 1) - 1, Sig: res.Sig}
+}
+
+// Columns method on Resource returns column heads identifying its format: heading row fields of
+// CSV resources (if any), or keys of JSON Lines and Parquet resources.
+func (res *Resource) Columns() (heads []string) {
+	switch res.Typ {
+	case RTcsv, RTxlsx:
+		if res.Heading && len(res.Split) > 0 {
+			for _, h := range res.Split[0] {
+				heads = append(heads, strings.TrimSpace(h))
+			}
+		}
+	case RTjsonl, RTparquet:
+		heads = append(heads, res.jkeys...)
+	}
+	return
+}
+
+// SetCols method on Resource sets column map Cols of an opened resource before Get, updating Heads
+// to match (so selected columns may be checked before rows are read).
+func (res *Resource) SetCols(cols string) error {
+	if res.stat != rsOPEN {
+		return fmt.Errorf("resource not open or already read")
+	}
+	res.Cols, res.Heads = cols, res.getHeads()
+	return nil
+}
+
+// InferCols method on Resource proposes a column map (explicit begin/end columns, with heads
+// from any heading row) and a fixed-field format specifier for a fixed-field resource, inferring
+// field boundaries from whitespace columns and character-class transitions in Preview rows.
+func (res *Resource) InferCols() (cols, spec string, e error) {
+	switch rows := res.Preview; {
+	case res.Typ != RTfixed:
+		return "", "", fmt.Errorf("column inference requires fixed-field resource")
+	case res.Heading && len(rows) < 3 || len(rows) < 2:
+		return "", "", fmt.Errorf("too few preview rows for column inference")
+	}
+	cols, spec = res.inferFixed()
+	return
+}
+
+// Register method on Resource adds resource format signature to SettingsCache as version "ver"
+// of its nearest format (recording resource column heads), applying the new Settings.
+func (res *Resource) Register(ver string) error {
+	switch {
+	case res.SettingsCache == nil:
+		return fmt.Errorf("no settings cache for format registration")
+	case res.Nearest == nil || res.Sig == "":
+		return fmt.Errorf("no nearest format to register resource format with")
+	}
+	item := res.SettingsCache.Get(res.Nearest.Sig)
+	item.Ver, item.Head, item.Date, item.Lock = ver, res.Columns(), time.Now(), false
+	res.SettingsCache.Set(res.Sig, &item)
+	res.Settings, res.Nearest = item, nil
+	return nil
+}
+
+// GetTyped method on Resource returns a receive channel over which the consumer may iterate rows
+// converted as specified by the Types column schema (with any conversion errors) and an error
+// channel which should be checked once receive channel is closed. Metadata ("~meta") rows are
+// skipped; delivery stops when the Resource is closed.
+func (res *Resource) GetTyped() (<-chan TypedRow, <-chan error) {
+	conv, e := parseTypes(res.Types)
+	if e != nil {
+		out, err := make(chan TypedRow, 1), make(chan error, 1)
+		err <- e
+		close(err)
+		close(out)
+		return out, err
+	}
+	in, err := res.Get()
+	out, sig := make(chan TypedRow, 64), res.sig
+	go func() {
+		defer close(out)
+		for row := range in {
+			if _, ok := row["~meta"]; ok {
+				continue
+			}
+			select {
+			case out <- conv.row(row):
+			case <-sig:
+				return
+			}
+		}
+	}()
+	return out, err
+}
+
+// Error method on RowError formats row problem description
+func (re *RowError) Error() string {
+	if re.Col != "" {
+		return fmt.Sprintf("line %d column %q: %s", re.Line, re.Col, re.Reason)
+	}
+	return fmt.Sprintf("line %d: %s", re.Line, re.Reason)
+}
+
+// Error method on SettingsConflict describes unresolved Settings.Sync conflicts
+func (sc *SettingsConflict) Error() string {
+	return fmt.Sprintf("%d unresolved settings conflict(s) in %q: %s", len(sc.Sigs), sc.Location, strings.Join(sc.Sigs, ", "))
+}
+
+// Close method on Resource closes resource and signals termination of upstream flow; resource
+// may not be re-opened.
+func (res *Resource) Close() error {
+	switch res.stat {
+	case rsNIL, rsCLOSED:
+		return fmt.Errorf("resource not open")
+	case rsOPEN:
+		close(res.isig)
+	case rsGET:
+		close(res.sig)
+	}
+	res.reader.Close()
+	res.stat = rsCLOSED
+	return nil
+}
+
+// Open method on ResourceSet opens members matched by Locations to detect their formats (closing
+// each once detected), returning an error if any fails to open or if any format is incompatible
+// with that of the first member. Members are compatible if of
+// the same type and either share a format signature or Settings Format, or (if unknown formats)
+// their column heads are at least Template MatchMin similar (0.8 if unset).
+func (set *ResourceSet) Open() (e error) {
+	if set.stat != rsNIL {
+		return fmt.Errorf("resource set must be uninitialized")
+	}
+	defer func() {
+		if e != nil {
+			set.Members = nil
+		}
+	}()
+
+	for _, loc := range set.Locations {
+		fns, _ := filepath.Glob(iio.ResolveName(loc))
+		if len(fns) == 0 {
+			fns = []string{loc}
+		}
+		for _, fn := range fns {
+			res := set.Template
+			res.Location, res.Resume, res.Checkpoints = fn, nil, false
+			if e = res.Open(nil); e != nil {
+				return fmt.Errorf("error opening %q: %v", fn, e)
+			}
+			res.Close()
+			set.Members = append(set.Members, &res)
+		}
+	}
+	if len(set.Members) == 0 {
+		return fmt.Errorf("no resources in set")
+	}
+
+	first, heads, bad := set.Members[0], make(map[string]bool), []string(nil)
+	for _, res := range set.Members {
+		if !first.compatible(res) {
+			bad = append(bad, res.Location)
+			continue
+		}
+		for _, h := range res.getHeads() {
+			if !heads[h] {
+				set.Heads, heads[h] = append(set.Heads, h), true
+			}
+		}
+	}
+	if set.Format = first.Settings.Format; set.Format == "" {
+		set.Format = first.Sig
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("%d resource(s) incompatible with %q format of %q: %s", len(bad), set.Format, first.Location,
+			strings.Join(bad, ", "))
+	}
+	set.stat = rsOPEN
+	return nil
+}
+
+// Get method on ResourceSet returns a receive channel over which the consumer may iterate rows of
+// each member in turn (keyed as for Resource Get, with member Location keyed by "~file" and union
+// Heads absent from the member keyed with empty values), and an error channel which should be
+// checked once receive channel is closed; each member is opened as it is reached and closed once
+// exhausted.
+func (set *ResourceSet) Get() (<-chan map[string]string, <-chan error) {
+	switch set.stat {
+	case rsNIL, rsCLOSED:
+		out, err := make(chan map[string]string, 1), make(chan error, 1)
+		err <- fmt.Errorf("resource set not open")
+		close(err)
+		close(out)
+		return out, err
+	case rsGET:
+		return set.out, set.err
+	}
+
+	set.stat = rsGET
+	set.out, set.err, set.sig = make(chan map[string]string, 64), make(chan error, 1), make(chan int)
+	set.rerr = make(chan *RowError, 64)
+	go func() {
+		var ewg sync.WaitGroup
+		defer func() {
+			if e := recover(); e != nil {
+				set.err <- e.(error)
+			}
+			ewg.Wait()
+			close(set.rerr)
+			close(set.err)
+			close(set.out)
+		}()
+
+		for _, m := range set.Members {
+			res := set.Template
+			res.Location, res.Resume, res.Checkpoints = m.Location, nil, false
+			if e := res.Open(nil); e != nil {
+				panic(fmt.Errorf("error opening %q: %v", res.Location, e))
+			}
+			if !set.getMember(&res, &ewg) {
+				return
+			}
+		}
+	}()
+	return set.out, set.err
+}
+
+// RowErrs method on ResourceSet returns a receive channel (following Get) over which malformed
+// rows of members are reported (with File set) as for Resource RowErrs.
+func (set *ResourceSet) RowErrs() <-chan *RowError {
+	if set.rerr == nil {
+		rerr := make(chan *RowError)
+		close(rerr)
+		return rerr
+	}
+	return set.rerr
+}
+
+// Close method on ResourceSet signals termination of upstream flow (closing any member being read);
+// the resource set may not be re-opened.
+func (set *ResourceSet) Close() error {
+	switch set.stat {
+	case rsNIL, rsCLOSED:
+		return fmt.Errorf("resource set not open")
+	case rsGET:
+		close(set.sig)
+	}
+	set.stat = rsCLOSED
+	return nil
+}
+
+// Cache method on Settings reads JSON-encoded format settings resource into cache.
+func (set *Settings) Cache(r io.Reader) {
+	if set == nil {
+		return
+	}
+	defer set.mutex.Unlock()
+	if set.mutex.Lock(); r == nil {
+		switch file, err := os.Open(iio.ResolveName(set.Location)); err {
+		case nil:
+			defer file.Close()
+			r = file
+		default:
+			set.cache, set.base, set.dirty = make(map[string]SettingsItem), nil, make(map[string]bool)
+			set.writable = os.IsNotExist(err)
+			return
+		}
+	}
+
+	switch b, err := io.ReadAll(r); err {
+	case nil:
+		if err = json.Unmarshal(b, &set.cache); err != nil {
+			set.cache = make(map[string]SettingsItem)
+		}
+		set.writable = err == nil
+	default:
+		set.cache = make(map[string]SettingsItem)
+	}
+	set.base, set.dirty = make(map[string]SettingsItem, len(set.cache)), make(map[string]bool)
+	for sig, item := range set.cache {
+		set.base[sig] = item
+	}
+}
+
+// Sync method on Settings merges cached format settings with those concurrently written to the
+// JSON settings resource (under an advisory lock), and atomically replaces the resource with the
+// merged settings. Items Set since the last Sync replace unchanged items on disk; items changed
+// on both sides are resolved in favor of Lock, then later Date. Settings on disk are retained for
+// unresolved conflicts, reported with a *SettingsConflict error.
+func (set *Settings) Sync() error {
+	if set == nil || set.cache == nil || set.Location == "" {
+		return fmt.Errorf("can't write settings cache")
+	} else if !set.writable {
+		return fmt.Errorf("can't write settings cache to %q", set.Location)
+	}
+	defer set.mutex.Unlock()
+	set.mutex.Lock()
+
+	fn := iio.ResolveName(set.Location)
+	unlock, err := lockFile(fn + ".lock")
+	if err != nil {
+		return fmt.Errorf("can't lock settings cache %q (%v)", set.Location, err)
+	}
+	defer unlock()
+
+	disk := make(map[string]SettingsItem)
+	switch b, err := ioutil.ReadFile(fn); {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("can't re-read settings cache %q (%v)", set.Location, err)
+	case json.Unmarshal(b, &disk) != nil:
+		return fmt.Errorf("can't merge settings cache with invalid %q", set.Location)
+	}
+	merged, conflicts := mergeSettings(disk, set.base, set.cache, set.dirty)
+
+	b, err := json.MarshalIndent(merged, "", "\t")
+	if err != nil {
+		return fmt.Errorf("can't write settings cache to %q (%v)", set.Location, err)
+	} else if err = replaceFile(fn, b); err != nil {
+		return fmt.Errorf("can't write settings cache to %q (%v)", set.Location, err)
+	}
+	set.cache, set.base, set.dirty = merged, make(map[string]SettingsItem, len(merged)), make(map[string]bool)
+	for sig, item := range merged {
+		set.base[sig] = item
+	}
+	if len(conflicts) > 0 {
+		return &SettingsConflict{Location: set.Location, Sigs: conflicts}
+	}
+	return nil
+}
+
+// Find method on Settings returns true if format signature exists in the cache.
+func (set *Settings) Find(sig string) (found bool) {
+	if set == nil || set.cache == nil || sig == "" {
+		return
+	}
+	defer set.mutex.Unlock()
+	set.mutex.Lock()
+
+	_, found = set.cache[sig]
+	return
+}
+
+// Get method on Settings returns cache item located by format signature.
+func (set *Settings) Get(sig string) (i SettingsItem) {
+	if set == nil || set.cache == nil || sig == "" {
+		return
+	}
+	defer set.mutex.Unlock()
+	set.mutex.Lock()
+
+	i, _ = set.cache[sig]
+	return
+}
+
+// GetSpecs method on Settings returns list of format specifications in the cache.
+func (set *Settings) GetSpecs() (specs []string) {
+	if set == nil || set.cache == nil {
+		return
+	}
+	defer set.mutex.Unlock()
+	set.mutex.Lock()
+
+	for sig := range set.cache {
+		if strings.HasPrefix(sig, "=") && len(sig) > 2 {
+			specs = append(specs, sig)
+		}
+	}
+	return
+}
+
+// Nearest method on Settings returns the cached format most similar to column heads "heads" by
+// column overlap and order (nil if none overlap); formats without recorded heads are compared
+// by heads of their column maps.
+func (set *Settings) Nearest(heads []string) (fm *FormatMatch) {
+	if set == nil || set.cache == nil || len(heads) == 0 {
+		return
+	}
+	defer set.mutex.Unlock()
+	set.mutex.Lock()
+
+	for sig, item := range set.cache {
+		var m FormatMatch
+		if len(item.Head) > 0 {
+			m.Score, m.Added, m.Removed = similarity(heads, item.Head, false)
+		} else if ch := cmapHeads(item.Cols); len(ch) > 0 {
+			m.Score, _, m.Removed = similarity(heads, ch, true)
+		}
+		if m.Score > 0 && (fm == nil || m.Score > fm.Score || m.Score == fm.Score && sig < fm.Sig) {
+			m.Sig, fm = sig, &m
+		}
+	}
+	return
+}
+
+// Set method on Settings returns item set (added or updated) in cache indexed under format
+// signature.
+func (set *Settings) Set(sig string, item *SettingsItem) *SettingsItem {
+	if set == nil || set.cache == nil || sig == "" {
+		return nil
+	}
+	defer set.mutex.Unlock()
+	set.mutex.Lock()
+
+	if set.cache[sig] = *item; set.dirty == nil {
+		set.dirty = make(map[string]bool)
+	}
+	set.dirty[sig] = true
+	return item
+}
+
+// Open method on Writer prepares writer for Put method encoding to "w" (or to a file created at
+// Location if nil), writing a heading row if specified.
+func (wr *Writer) Open(w io.Writer) error {
+	if wr.w != nil {
+		return fmt.Errorf("writer already open")
+	}
+	switch wr.Typ {
+	case RTunk, RTcsv:
+		if wr.Typ = RTcsv; wr.Sep == '\x00' {
+			wr.Sep = ','
+		}
+	case RTfixed:
+		if wr.Cols == "" {
+			return fmt.Errorf("fixed-field writer requires column map")
+		}
+	default:
+		return fmt.Errorf("unsupported writer type")
+	}
+	if e := checkCMap(wr.Cols); e != nil {
+		return e
+	}
+	wr.setLayout()
+
+	if w == nil {
+		f, e := os.Create(iio.ResolveName(wr.Location))
+		if e != nil {
+			return e
+		}
+		w, wr.file = f, f
+	}
+	if wr.w = w; wr.Heading && len(wr.Heads) > 0 {
+		fields := make([]string, 0, len(wr.layout))
+		for _, c := range wr.layout {
+			fields = append(fields, c.head)
+		}
+		return wr.put(fields)
+	}
+	return nil
+}
+
+// Put method on Writer encodes a key-value map row (as returned by Resource Get) into columns as
+// specified by Cols (or Heads); rows excluded by column map filters are not written.
+func (wr *Writer) Put(row map[string]string) error {
+	if wr.w == nil {
+		return fmt.Errorf("writer not open")
+	}
+	for _, c := range wr.filters {
+		if !c.item.pass(row[c.head]) {
+			return nil
+		}
+	}
+	fields := make([]string, len(wr.layout))
+	for i, c := range wr.layout {
+		if fields[i] = row[c.head]; !c.item.pass(fields[i]) {
+			return nil
+		}
+	}
+	return wr.put(fields)
+}
+
+// PutFields method on Writer encodes a row of fields, in column map order if specified.
+func (wr *Writer) PutFields(fields []string) error {
+	if wr.w == nil {
+		return fmt.Errorf("writer not open")
+	}
+	return wr.put(fields)
+}
+
+// Close method on Writer ends encoding, closing any file it created.
+func (wr *Writer) Close() (e error) {
+	if wr.w == nil {
+		return fmt.Errorf("writer not open")
+	}
+	if wr.file != nil {
+		e = wr.file.Close()
+	}
+	wr.w, wr.file = nil, nil
+	return
+}
@@ -0,0 +1,178 @@
+package csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// getRows returns rows (excluding metadata) read by Get from resource "res" once opened
+func getRows(t *testing.T, res *Resource) []map[string]string {
+	t.Helper()
+	if e := res.Open(nil); e != nil {
+		t.Fatalf("Open: %v", e)
+	}
+	defer res.Close()
+	in, err := res.Get()
+	var rows []map[string]string
+	for row := range in {
+		if _, ok := row["~meta"]; !ok {
+			rows = append(rows, row)
+		}
+	}
+	if e := <-err; e != nil {
+		t.Fatalf("Get: %v", e)
+	}
+	return rows
+}
+
+func TestJSONLEscapedQuote(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "esc.jsonl")
+	content := `{"id":1,"note":"say \"hi\""}` + "\n" +
+		`{"id":2,"note":"ends with \""}` + "\n" +
+		`{"id":3,"note":"back\\"}` + "\n" +
+		`{"id":4,"note":"\"quoted, with comma\""}` + "\n"
+	if e := os.WriteFile(fn, []byte(content), 0644); e != nil {
+		t.Fatal(e)
+	}
+	res := &Resource{Location: fn}
+	rows := getRows(t, res)
+	if res.Typ != RTjsonl {
+		t.Fatalf("type %v, want JSON Lines", res.Typ)
+	}
+	want := []string{`say "hi"`, `ends with "`, `back\`, `"quoted, with comma"`}
+	if len(rows) != len(want) {
+		t.Fatalf("read %d rows, want %d: %v", len(rows), len(want), rows)
+	}
+	for i, row := range rows {
+		if row["note"] != want[i] {
+			t.Errorf("row %d note %q, want %q", i+1, row["note"], want[i])
+		}
+	}
+}
+
+func TestLatin1AfterSample(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "late.csv")
+	b := []byte("id,name\n")
+	for i := 0; i < 500; i++ {
+		b = append(b, fmt.Sprintf("%d,plain name\n", i)...)
+	}
+	b = append(b, "999,Jos\xe9 M\xfcller\n"...)
+	if e := os.WriteFile(fn, b, 0644); e != nil {
+		t.Fatal(e)
+	}
+	rows := getRows(t, &Resource{Location: fn})
+	if len(rows) != 501 {
+		t.Fatalf("read %d rows, want 501", len(rows))
+	}
+	if v := rows[500]["name"]; v != "José Müller" {
+		t.Errorf("name %q, want %q", v, "José Müller")
+	}
+}
+
+func TestInferColsRagged(t *testing.T) {
+	res := &Resource{Typ: RTfixed, Heading: true, Preview: []string{
+		"ID   NAME      AMOUNT F",
+		"001  al",
+		"002  bob       12.50  X",
+		"003  carol     7.25   X",
+		"004  dave      100.00 X",
+	}}
+	cols, spec, e := res.InferCols()
+	if e != nil {
+		t.Fatalf("InferCols: %v", e)
+	}
+	if want := "ID:1:5,NAME:6:15,AMOUNT:16:22,F:23:23"; cols != want {
+		t.Errorf("column map %q, want %q", cols, want)
+	}
+	if spec == "" {
+		t.Error("no specifier inferred")
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	tests := []struct {
+		name, content string
+	}{
+		{"ck.csv", "id,name\n1,a\n2,\"b\nb\"\n3,c\n4,d\n5,e\n"},
+		{"ck.jsonl", "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\\\"\"}\n{\"id\":3,\"name\":\"c\"}\n{\"id\":4,\"name\":\"d\"}\n{\"id\":5,\"name\":\"e\"}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := filepath.Join(t.TempDir(), tt.name)
+			if e := os.WriteFile(fn, []byte(tt.content), 0644); e != nil {
+				t.Fatal(e)
+			}
+			if rows := getRows(t, &Resource{Location: fn}); len(rows) != 5 || rows[0]["~off"] != "" {
+				t.Fatalf("rows %v: want 5 without offsets", rows)
+			}
+			res := &Resource{Location: fn, Checkpoints: true}
+			rows := getRows(t, res)
+			if len(rows) != 5 {
+				t.Fatalf("read %d rows, want 5", len(rows))
+			}
+			ck := res.Checkpoint(rows[1])
+			rest := getRows(t, &Resource{Location: fn, Resume: &ck})
+			if len(rest) != 3 || rest[0]["id"] != "3" || rest[0]["~line"] != rows[2]["~line"] {
+				t.Errorf("resumed rows %v, want ids 3-5", rest)
+			}
+		})
+	}
+}
+
+func TestResourceSet(t *testing.T) {
+	dir := t.TempDir()
+	for fn, heads := range map[string]string{"a.csv": "id,name,region,usage,cost", "b.csv": "id,name,region,usage,cost,note"} {
+		var sb strings.Builder
+		sb.WriteString(heads + "\n")
+		for i := 0; i < 20; i++ {
+			fmt.Fprintf(&sb, "%d,n%d,r%d,%d.5,%d.25", i, i, i%3, i, i)
+			if strings.HasSuffix(heads, "note") {
+				sb.WriteString(",x")
+			}
+			sb.WriteString("\n")
+		}
+		if e := os.WriteFile(filepath.Join(dir, fn), []byte(sb.String()), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	set := &ResourceSet{Locations: []string{filepath.Join(dir, "*.csv")}}
+	if e := set.Open(); e != nil {
+		t.Fatalf("Open: %v", e)
+	}
+	defer set.Close()
+	if len(set.Members) != 2 || strings.Join(set.Heads, ",") != "id,name,region,usage,cost,note" {
+		t.Fatalf("%d members with heads %v", len(set.Members), set.Heads)
+	}
+	for _, res := range set.Members {
+		if res.Close() == nil {
+			t.Errorf("member %q left open before Get", res.Location)
+		}
+	}
+	in, err := set.Get()
+	n := 0
+	for row := range in {
+		if _, ok := row["~meta"]; ok {
+			continue
+		}
+		note, ok := row["note"]
+		switch n++; filepath.Base(row["~file"]) {
+		case "a.csv":
+			if !ok || note != "" {
+				t.Errorf("a.csv row %v: want empty note", row)
+			}
+		case "b.csv":
+			if note != "x" {
+				t.Errorf("b.csv row %v: want note x", row)
+			}
+		default:
+			t.Errorf("row %v from unknown member", row)
+		}
+	}
+	if e := <-err; e != nil || n != 40 {
+		t.Errorf("read %d rows: %v", n, e)
+	}
+}
@@ -0,0 +1,431 @@
+package csv
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type (
+	// cexpr compiled derived-column expression, returning its value for a row (false if invalid)
+	cexpr func(row map[string]string) (string, bool)
+
+	// dcol derived column of a column map
+	dcol struct {
+		head string   // derived column head
+		item cmapItem // derived column filters (skip: filter-only column)
+		expr cexpr    // derived column expression
+	}
+
+	// exprParser derived-column expression parser state
+	exprParser struct {
+		s string // expression source
+		p int    // parse position
+	}
+)
+
+// splitBraced splits "s" into substrings separated by "sep" outside of (balanced) braces
+func splitBraced(s string, sep byte) (v []string) {
+	depth, b := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth == 0 {
+				v, b = append(v, s[b:i]), i+1
+			}
+		}
+	}
+	return append(v, s[b:])
+}
+
+// addFilter method on cmapItem adds brace-enclosed column map filter "f" (prefix, regex or numeric
+// range), returning false if "f" is not a filter; malformed regex or range operands panic
+func (c *cmapItem) addFilter(f string) bool {
+	switch {
+	case !strings.HasSuffix(f, "}"):
+		return false
+	case strings.HasPrefix(f, "={"), strings.HasPrefix(f, "!{"):
+		p := cmapItem{inclusive: f[0] == '=', prefix: strings.Split(f[2:len(f)-1], ":")}
+		if len(c.prefix) == 0 {
+			c.inclusive, c.prefix = p.inclusive, p.prefix
+		} else {
+			c.tests = append(c.tests, p.pass)
+		}
+	case strings.HasPrefix(f, "~{"), strings.HasPrefix(f, "!~{"):
+		neg := f[0] == '!'
+		re, e := regexp.Compile(f[strings.IndexByte(f, '{')+1 : len(f)-1])
+		if e != nil {
+			panic(fmt.Errorf("column map filter %q: %v", f, e))
+		}
+		c.tests = append(c.tests, func(s string) bool { return re.MatchString(s) != neg })
+	case strings.HasPrefix(f, "#{"):
+		v := strings.SplitN(f[2:len(f)-1], "..", 2)
+		lo, hi, e := math.Inf(-1), math.Inf(1), error(nil)
+		if len(v) != 2 {
+			panic(fmt.Errorf("column map filter %q: range requires '<lo>..<hi>'", f))
+		}
+		if s := strings.TrimSpace(v[0]); s != "" {
+			lo, e = strconv.ParseFloat(s, 64)
+		}
+		if s := strings.TrimSpace(v[1]); s != "" && e == nil {
+			hi, e = strconv.ParseFloat(s, 64)
+		}
+		if e != nil {
+			panic(fmt.Errorf("column map filter %q: invalid range bound", f))
+		}
+		c.tests = append(c.tests, func(s string) bool {
+			n, e := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			return e == nil && n >= lo && n <= hi
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// addFilters method on cmapItem adds column map filters "fs", returning false if any is not a
+// filter
+func (c *cmapItem) addFilters(fs []string) bool {
+	for _, f := range fs {
+		if !c.addFilter(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// filtered method on cmapItem returns true if column map item has any filters
+func (c cmapItem) filtered() bool {
+	return len(c.prefix) > 0 || len(c.tests) > 0
+}
+
+// checkCMap returns an error if column map "cmap" has malformed filters or derived columns
+func checkCMap(cmap string) (e error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e = r.(error)
+		}
+	}()
+	parseCMap(cmap, false, 0xffff)
+	parseDerived(cmap)
+	return
+}
+
+// parseDerived returns derived columns of column map "cmap" in map order; malformed expressions
+// or filters panic
+//   derived-column syntax (applies to all resource types):
+//		"[!]<head>:@{<expr>}[:<filter>]..."
+//   expressions combine column references ("[<head>]" of selected or earlier derived columns),
+//   quoted string and numeric literals, arithmetic (+ - * / and parentheses on numeric values)
+//   and functions:
+//		cat(<s>,...)  sub(<s>,<begin>[,<len>])  date(<s>,<in layout>,<out layout>)
+//		trim(<s>)  upper(<s>)  lower(<s>)  round(<n>[,<places>])
+//   examples (date layouts are Go reference-time layouts):
+//		"name:@{cat([last],', ',[first])}"
+//		"total:@{round([qty]*[rate],2)}:#{0.01..}"
+//		"day:@{date(sub([start],1,10),'2006-01-02','01/02/2006')}"
+func parseDerived(cmap string) (der []dcol) {
+	if cmap == "" {
+		return
+	}
+	for _, t := range splitBraced(cmap, ',') {
+		v, a := splitBraced(t, ':'), 1
+		for ; a < len(v) && !strings.HasPrefix(v[a], "@{"); a++ {
+		}
+		if a == len(v) || !strings.HasSuffix(v[a], "}") {
+			continue
+		}
+		h, d := strings.Join(v[:a], ":"), dcol{}
+		if !d.item.addFilters(v[a+1:]) {
+			panic(fmt.Errorf("column map filters invalid for derived column %q", h))
+		}
+		if d.head, d.item.skip = strings.TrimLeft(h, "!"), strings.HasPrefix(h, "!"); d.head == "" {
+			continue
+		}
+		ep := exprParser{s: v[a][2 : len(v[a])-1]}
+		if d.expr = ep.expr(); ep.peek() != 0 {
+			panic(fmt.Errorf("derived column %q: unexpected %q in expression", d.head, ep.s[ep.p:]))
+		}
+		der = append(der, d)
+	}
+	return
+}
+
+// derive method on Resource adds derived columns to row "m", returning false if a derived column
+// filter excludes the row
+func (res *Resource) derive(m map[string]string) bool {
+	if len(res.der) == 0 {
+		return true
+	}
+	for _, d := range res.der {
+		v, _ := d.expr(m)
+		if !d.item.pass(v) {
+			return false
+		} else if v != "" {
+			m[d.head] = v
+		}
+	}
+	for _, d := range res.der {
+		if d.item.skip {
+			delete(m, d.head)
+		}
+	}
+	return true
+}
+
+// skip method on exprParser advances past whitespace
+func (ep *exprParser) skip() {
+	for ep.p < len(ep.s) && unicode.IsSpace(rune(ep.s[ep.p])) {
+		ep.p++
+	}
+}
+
+// peek method on exprParser returns the next non-whitespace byte (0 at end of expression)
+func (ep *exprParser) peek() byte {
+	if ep.skip(); ep.p < len(ep.s) {
+		return ep.s[ep.p]
+	}
+	return 0
+}
+
+// fail method on exprParser panics with an expression syntax error
+func (ep *exprParser) fail(msg string) {
+	panic(fmt.Errorf("derived column expression %q: %s at offset %d", ep.s, msg, ep.p))
+}
+
+// expr method on exprParser compiles an additive expression: <term> [(+|-) <term>]...
+func (ep *exprParser) expr() cexpr {
+	x := ep.term()
+	for op := ep.peek(); op == '+' || op == '-'; op = ep.peek() {
+		ep.p++
+		x = arith(op, x, ep.term())
+	}
+	return x
+}
+
+// term method on exprParser compiles a multiplicative expression: <factor> [(*|/) <factor>]...
+func (ep *exprParser) term() cexpr {
+	x := ep.factor()
+	for op := ep.peek(); op == '*' || op == '/'; op = ep.peek() {
+		ep.p++
+		x = arith(op, x, ep.factor())
+	}
+	return x
+}
+
+// factor method on exprParser compiles a unary expression, parenthesized expression, column
+// reference, literal or function call
+func (ep *exprParser) factor() cexpr {
+	switch c := ep.peek(); {
+	case c == '-':
+		ep.p++
+		return arith('-', func(map[string]string) (string, bool) { return "0", true }, ep.factor())
+	case c == '(':
+		ep.p++
+		x := ep.expr()
+		if ep.peek() != ')' {
+			ep.fail("missing ')'")
+		}
+		ep.p++
+		return x
+	case c == '[':
+		e := strings.IndexByte(ep.s[ep.p:], ']')
+		if e < 0 {
+			ep.fail("missing ']'")
+		}
+		h := ep.s[ep.p+1 : ep.p+e]
+		ep.p += e + 1
+		return func(row map[string]string) (string, bool) {
+			v, ok := row[h]
+			return v, ok
+		}
+	case c == '\'' || c == '"':
+		e := strings.IndexByte(ep.s[ep.p+1:], c)
+		if e < 0 {
+			ep.fail("unterminated string")
+		}
+		s := ep.s[ep.p+1 : ep.p+1+e]
+		ep.p += e + 2
+		return func(map[string]string) (string, bool) { return s, true }
+	case c >= '0' && c <= '9' || c == '.':
+		b := ep.p
+		for ep.p < len(ep.s) && (ep.s[ep.p] >= '0' && ep.s[ep.p] <= '9' || ep.s[ep.p] == '.') {
+			ep.p++
+		}
+		s := ep.s[b:ep.p]
+		if _, e := strconv.ParseFloat(s, 64); e != nil {
+			ep.fail("invalid number")
+		}
+		return func(map[string]string) (string, bool) { return s, true }
+	case unicode.IsLetter(rune(c)):
+		b := ep.p
+		for ep.p < len(ep.s) && unicode.IsLetter(rune(ep.s[ep.p])) {
+			ep.p++
+		}
+		name := ep.s[b:ep.p]
+		if ep.peek() != '(' {
+			ep.fail(fmt.Sprintf("missing '(' after %q", name))
+		}
+		ep.p++
+		var args []cexpr
+		for ep.peek() != ')' {
+			if args = append(args, ep.expr()); ep.peek() == ',' {
+				ep.p++
+			} else if ep.peek() != ')' {
+				ep.fail("missing ')'")
+			}
+		}
+		ep.p++
+		return ep.call(name, args)
+	case c == 0:
+		ep.fail("unexpected end")
+	}
+	ep.fail("unexpected character")
+	return nil
+}
+
+// call method on exprParser compiles function "name" call on "args"
+func (ep *exprParser) call(name string, args []cexpr) cexpr {
+	nargs := func(min, max int) {
+		if len(args) < min || len(args) > max {
+			ep.fail(fmt.Sprintf("%s() takes %d-%d arguments", name, min, max))
+		}
+	}
+	eval := func(row map[string]string) (v []string, ok bool) {
+		v = make([]string, len(args))
+		for i, a := range args {
+			if v[i], ok = a(row); !ok {
+				return
+			}
+		}
+		return v, true
+	}
+	unary := func(fn func(string) string) cexpr {
+		nargs(1, 1)
+		return func(row map[string]string) (string, bool) {
+			v, ok := args[0](row)
+			return fn(v), ok
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case "cat":
+		return func(row map[string]string) (string, bool) {
+			var b strings.Builder
+			for _, a := range args {
+				if v, ok := a(row); ok {
+					b.WriteString(v)
+				}
+			}
+			return b.String(), true
+		}
+	case "sub":
+		nargs(2, 3)
+		return func(row map[string]string) (string, bool) {
+			v, ok := eval(row)
+			if !ok {
+				return "", false
+			}
+			r, b, n := []rune(v[0]), atoi(v[1], 1), -1
+			if len(v) > 2 {
+				n = atoi(v[2], -1)
+			}
+			switch {
+			case b < 0:
+				b = len(r) + b
+			case b > 0:
+				b--
+			}
+			if b < 0 || b > len(r) {
+				return "", false
+			} else if n < 0 || b+n > len(r) {
+				n = len(r) - b
+			}
+			return string(r[b : b+n]), true
+		}
+	case "date":
+		nargs(3, 3)
+		return func(row map[string]string) (string, bool) {
+			v, ok := eval(row)
+			if !ok {
+				return "", false
+			}
+			t, e := time.Parse(v[1], strings.TrimSpace(v[0]))
+			if e != nil {
+				return "", false
+			}
+			return t.Format(v[2]), true
+		}
+	case "trim":
+		return unary(strings.TrimSpace)
+	case "upper":
+		return unary(strings.ToUpper)
+	case "lower":
+		return unary(strings.ToLower)
+	case "round":
+		nargs(1, 2)
+		return func(row map[string]string) (string, bool) {
+			v, ok := eval(row)
+			if !ok {
+				return "", false
+			}
+			n, e := strconv.ParseFloat(strings.TrimSpace(v[0]), 64)
+			if e != nil {
+				return "", false
+			}
+			d := 0
+			if len(v) > 1 {
+				d = atoi(v[1], 0)
+			}
+			return strconv.FormatFloat(n, 'f', d, 64), true
+		}
+	}
+	ep.fail(fmt.Sprintf("unknown function %q", name))
+	return nil
+}
+
+// arith returns an expression applying arithmetic operator "op" to numeric operands "x" and "y"
+func arith(op byte, x, y cexpr) cexpr {
+	return func(row map[string]string) (string, bool) {
+		xs, ok := x(row)
+		if !ok {
+			return "", false
+		}
+		ys, ok := y(row)
+		if !ok {
+			return "", false
+		}
+		a, e := strconv.ParseFloat(strings.TrimSpace(xs), 64)
+		if e != nil {
+			return "", false
+		}
+		b, e := strconv.ParseFloat(strings.TrimSpace(ys), 64)
+		if e != nil {
+			return "", false
+		}
+		switch op {
+		case '+':
+			a += b
+		case '-':
+			a -= b
+		case '*':
+			a *= b
+		case '/':
+			if b == 0 {
+				return "", false
+			}
+			a /= b
+		}
+		return strconv.FormatFloat(a, 'f', -1, 64), true
+	}
+}
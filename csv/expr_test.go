@@ -0,0 +1,145 @@
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	row := map[string]string{"a": "6", "b": "4", "s": "Hello World", "d": "2024-03-05 10:20:00", "x": "abc", "n": " 2.5 "}
+	tests := []struct {
+		expr, want string
+		ok         bool
+	}{
+		{"1+2*3", "7", true},
+		{"(1+2)*3", "9", true},
+		{"10-4-3", "3", true},
+		{"12/3/2", "2", true},
+		{"[a]+[b]*2-1", "13", true},
+		{"[a]/[b]", "1.5", true},
+		{" [n] * 2 ", "5", true},
+		{"-[a]+10", "4", true},
+		{"--2", "2", true},
+		{"2*-3", "-6", true},
+		{"-(1+2)*2", "-6", true},
+		{"-[a]*-[b]", "24", true},
+		{"[a]/0", "", false},
+		{"[x]+1", "", false},
+		{"[missing]", "", false},
+		{"'str'", "str", true},
+		{`"dq"`, "dq", true},
+		{"cat([missing],'-',[a])", "-6", true},
+		{"cat([s],' ',[a]+1)", "Hello World 7", true},
+		{"sub([s],1,5)", "Hello", true},
+		{"sub([s],7)", "World", true},
+		{"sub([s],-5)", "World", true},
+		{"sub([s],-5,3)", "Wor", true},
+		{"sub([s],-1)", "d", true},
+		{"sub([s],3,100)", "llo World", true},
+		{"sub([s],12)", "", true},
+		{"sub([s],13)", "", false},
+		{"sub([s],-20)", "", false},
+		{"sub([missing],1)", "", false},
+		{"date([d],'2006-01-02 15:04:05','01/02/2006')", "03/05/2024", true},
+		{"date(sub([d],1,10),'2006-01-02','Jan 2, 2006')", "Mar 5, 2024", true},
+		{"date([d],'2006-01-02 15:04:05','15h04')", "10h20", true},
+		{"date([x],'2006-01-02','01/02/2006')", "", false},
+		{"round([a]/[b]*1.005,2)", "1.51", true},
+		{"round([n])", "2", true},
+		{"round([x],2)", "", false},
+		{"upper(trim('  ab '))", "AB", true},
+		{"LOWER([s])", "hello world", true},
+	}
+	for _, tt := range tests {
+		ep := exprParser{s: tt.expr}
+		x := ep.expr()
+		if ep.peek() != 0 {
+			t.Errorf("%q: unparsed %q", tt.expr, ep.s[ep.p:])
+			continue
+		}
+		if v, ok := x(row); v != tt.want || ok != tt.ok {
+			t.Errorf("%q: %q (%v), want %q (%v)", tt.expr, v, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDerived(t *testing.T) {
+	tests := []struct {
+		cmap string
+		row  map[string]string
+		want map[string]string // nil if row excluded
+	}{
+		{"total:@{[qty]*[rate]}:#{10..100}", map[string]string{"qty": "3", "rate": "5"}, map[string]string{"total": "15"}},
+		{"total:@{[qty]*[rate]}:#{10..100}", map[string]string{"qty": "3", "rate": "50"}, nil},
+		{"total:@{[qty]*[rate]}:#{..9}", map[string]string{"qty": "3", "rate": "x"}, nil},
+		{"total:@{[qty]*[rate]}:#{-5..}", map[string]string{"qty": "-1", "rate": "2"}, map[string]string{"total": "-2"}},
+		{"code:@{upper([c])}:~{^A[0-9]+$}", map[string]string{"c": "a12"}, map[string]string{"code": "A12"}},
+		{"code:@{upper([c])}:~{^A[0-9]+$}", map[string]string{"c": "b12"}, nil},
+		{"code:@{[c]}:!~{test}", map[string]string{"c": "a test row"}, nil},
+		{"pfx:@{sub([c],1,2)}:={AB:CD}", map[string]string{"c": "CDxy"}, map[string]string{"pfx": "CD"}},
+		{"pfx:@{sub([c],1,2)}:!{AB:CD}", map[string]string{"c": "CDxy"}, nil},
+		{"pfx:@{sub([c],1,2)}:={A}:~{B$}", map[string]string{"c": "ABC"}, map[string]string{"pfx": "AB"}},
+		{"pfx:@{sub([c],1,2)}:={A}:~{B$}", map[string]string{"c": "ACB"}, nil},
+		{"!tmp:@{[a]+1}:#{2..},out:@{[tmp]*2}", map[string]string{"a": "2"}, map[string]string{"out": "6"}},
+		{"!tmp:@{[a]+1}:#{2..},out:@{[tmp]*2}", map[string]string{"a": "0"}, nil},
+		{"name:@{cat([last],', ',[first])}", map[string]string{"first": "Ada", "last": "Lovelace"}, map[string]string{"name": "Lovelace, Ada"}},
+		{"bad:@{[a]/0}", map[string]string{"a": "1"}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		res := &Resource{der: parseDerived(tt.cmap)}
+		row := make(map[string]string)
+		for h, v := range tt.row {
+			row[h] = v
+		}
+		switch pass := res.derive(row); {
+		case tt.want == nil && pass:
+			t.Errorf("%q %v: row not excluded (%v)", tt.cmap, tt.row, row)
+		case tt.want == nil:
+		case !pass:
+			t.Errorf("%q %v: row excluded", tt.cmap, tt.row)
+		default:
+			for h, v := range tt.want {
+				if row[h] != v {
+					t.Errorf("%q %v: %s = %q, want %q", tt.cmap, tt.row, h, row[h], v)
+				}
+			}
+			if len(row) != len(tt.row)+len(tt.want) {
+				t.Errorf("%q %v: derived row %v", tt.cmap, tt.row, row)
+			}
+		}
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	tests := []struct {
+		cmap, msg string
+	}{
+		{"x:@{1+}", "unexpected end at offset 2"},
+		{"x:@{(1+2}", "missing ')'"},
+		{"x:@{cat(1,2}", "missing ')'"},
+		{"x:@{[a}", "missing ']'"},
+		{"x:@{'abc}", "unterminated string"},
+		{"x:@{1.2.3}", "invalid number"},
+		{"x:@{$}", "unexpected character at offset 0"},
+		{"x:@{abs}", `missing '(' after "abs"`},
+		{"x:@{foo(1)}", `unknown function "foo"`},
+		{"x:@{sub([a])}", "sub() takes 2-3 arguments"},
+		{"x:@{date([a],'x')}", "date() takes 3-3 arguments"},
+		{"x:@{trim()}", "trim() takes 1-1 arguments"},
+		{"x:@{1 2}", `derived column "x": unexpected "2" in expression`},
+		{"x:@{1}:~{[}", `column map filter "~{[}"`},
+		{"x:@{1}:#{a..b}", "invalid range bound"},
+		{"x:@{1}:#{5}", "range requires '<lo>..<hi>'"},
+		{"x:@{1}:q", `column map filters invalid for derived column "x"`},
+	}
+	for _, tt := range tests {
+		if e := checkCMap(tt.cmap); e == nil || !strings.Contains(e.Error(), tt.msg) {
+			t.Errorf("%q: error %v, want %q", tt.cmap, e, tt.msg)
+		}
+	}
+	for _, cmap := range []string{"a,b:={x},c:@{round([a]*2,1)}:#{0..}", "d:@{date([t],'2006-01-02','Jan 2')}"} {
+		if e := checkCMap(cmap); e != nil {
+			t.Errorf("%q: %v", cmap, e)
+		}
+	}
+}
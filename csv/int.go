@@ -1,529 +1,1451 @@
-package csv
-
-import (
-	"crypto/md5"
-	"fmt"
-	"strconv"
-	"strings"
-	"unsafe"
-
-	"github.com/sententico/cost/internal/io"
-)
-
-type (
-	// resStat Resource state (see const)
-	resStat uint8
-
-	// cmapItem ...
-	cmapItem struct {
-		col, begin      int      // column reference (paired with begin column for fixed-field files)
-		skip, inclusive bool     // skip: filter-only columns; inclusive: prefix op (exclusive default)
-		prefix          []string // prefix operands
-	}
-)
-
-// Resource states
-const (
-	rsNIL    resStat = iota // initial state
-	rsOPEN                  // resource opened, peek-ahead completed
-	rsGET                   // channels opened to get resource contents
-	rsCLOSED                // resource closed (cannot be re-opened)
-)
-
-// general package constants
-const (
-	previewLines = 24       // maximum preview lines returned in Resource on Open (must be >2)
-	sepSet       = ",\t|;:" // priority of separator runes automatically checked if none specified
-	bigFieldLen  = 36       // mean field length above which CSV column-density is suspiciously low
-)
-
-var (
-	commentSet = [...]string{"#", "//", "'"}
-)
-
-// peekAhead reads initial lines of an opened resource, populating its identification fields. These
-// include a preview slice of raw data rows (excluding blank and comment lines), a total resource
-// row estimate, an indicator whether the first row is a heading, the comment prefix used (if any),
-// and if a CSV type, the field separator with trimmed fields of the preview data rows split by it.
-// A format signature (specifier or heading MD5 hash, as available) with any settings info mapped
-// to it (like file application type, version and default column selector-map) are also provided.
-func (res *Resource) peekAhead() {
-	// TODO: improve override handling (Typ, Sep, Cols, Comment, Shebang)
-	// comment/shebang may not have (") prefix
-	row, fix, tlen, sep, max, hash := -1, 0, 0, '\x00', 1, ""
-nextLine:
-	for ln := range res.peek {
-		switch {
-		case len(strings.TrimSpace(ln)) == 0:
-		case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang) || res.Comment != "" && strings.HasPrefix(ln, res.Comment):
-			if row < 0 {
-				row = 0
-			}
-		case row < 0:
-			row = 0
-			for _, p := range commentSet {
-				if strings.HasPrefix(ln, p) {
-					res.Comment, res.Shebang = p, p+"!"
-					continue nextLine
-				}
-			}
-			fallthrough
-		default:
-			switch row++; {
-			case row == 2:
-				fix = len(ln)
-			case len(ln) != fix:
-				fix = 0
-			}
-			tlen += len(ln)
-			res.Preview = append(res.Preview, ln)
-		}
-	}
-	switch {
-	case len(res.ierr) > 0:
-		panic(fmt.Errorf("problem peeking ahead on resource (%v)", <-res.ierr))
-	case row < 1:
-		res.Typ, res.Rows = RTempty, 0
-		return
-	case res.finfo != nil:
-		res.Rows = int(float64(res.finfo.Size())/float64(tlen-len(res.Preview[0])+row-1)*0.995+0.5) * (row - 1)
-	default:
-		res.Rows = -1
-	}
-
-nextSep:
-	for _, r := range string(res.Sep) + sepSet {
-		c, sl, sh := 0, []string{}, []string{}
-		for _, ln := range res.Preview {
-			if sl = io.SplitCSV(ln, r); len(sl) <= max || c > 0 && len(sl) != c {
-				continue nextSep
-			}
-			if c == 0 {
-				c, sh = len(sl), sl
-			}
-		}
-		if sep, max, hash = r, c, fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(sh, string(r))))); res.SettingsCache.Find(hash) {
-			break
-		}
-		qh := make(map[string]int, c)
-		for _, h := range sh {
-			h = strings.TrimSpace(h)
-			if _, e := strconv.ParseFloat(h, 64); e != nil && len(h) > 0 {
-				qh[h]++
-			}
-		}
-		if len(qh) != c {
-			hash = ""
-		}
-		if sep == res.Sep {
-			break
-		}
-	}
-
-	switch res.Sep = sep; {
-	case res.Sep == '\x00' && fix == 0:
-		// unknown resource type
-		res.Typ = RTunk
-	case res.Sep != '\x00' && fix != 0 && (res.Typ == RTfixed || res.Typ == RTunk && (hash == "" || fix/max > bigFieldLen)):
-		// ambigious resource type, but evidence for CSV is weak
-		fallthrough
-	case res.Sep == '\x00':
-		// fixed-field resource type
-		res.Typ, res.Heading = RTfixed, res.Heading || len(res.Preview[0]) != fix
-		res.Sig, res.Heading = res.findFSpec()
-	default:
-		// CSV resource type
-		for _, r := range res.Preview {
-			res.Split = append(res.Split, io.SplitCSV(r, res.Sep))
-		}
-		if res.Typ, res.Sig, res.Heading = RTcsv, hash, res.Heading || hash != ""; !res.SettingsCache.Find(res.Sig) {
-			if spec := res.findSpec(); spec != "" {
-				res.Sig, res.Heading = spec, spec[2] == '{'
-			}
-		}
-	}
-	if res.SettingsCache != nil {
-		if res.Settings = res.SettingsCache.Get(res.Sig); res.Cols == "" {
-			res.Cols = res.Settings.Cols
-		}
-	}
-	res.Heads = res.getHeads()
-}
-
-// getCSV method on Resource reads CSV rows, writing them to "out" channel once converted into
-// key-value maps as specified in Cols until CSV input is exhausted or "sig" indicates a halt
-func (res *Resource) getCSV() {
-	vcols, wid, line, algn, skip := make(map[string]cmapItem, 32), 0, 0, 0, false
-	head := res.Heading
-	for ln := range res.in {
-		for line++; ; {
-			switch {
-			case len(strings.TrimSpace(ln)) == 0:
-			case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang):
-				select {
-				case res.out <- map[string]string{"~meta": ln[len(res.Shebang):], "~line": strconv.Itoa(line)}:
-				case <-res.sig:
-					return
-				}
-			case res.Comment != "" && strings.HasPrefix(ln, res.Comment):
-			case len(vcols) == 0:
-				sl, uc, vs := io.SplitCSV(ln, res.Sep), make(map[int]int), 0
-				wid = len(sl)
-				pc, ps := parseCMap(res.Cols, false, wid)
-				for _, c := range pc {
-					uc[c.col]++
-				}
-				for i, h := range sl {
-					if h != "" && (ps == 0 || pc[h].col > 0) {
-						c := pc[h]
-						c.col = i + 1
-						vcols[h] = c
-					}
-				}
-				for _, c := range vcols {
-					if !c.skip {
-						vs++
-					}
-				}
-				switch {
-				case ps == 0 && (!head || len(vcols) != wid):
-					panic(fmt.Errorf("can't read CSV resource without internal column heads or map"))
-				case ps == 0 && vs == 0:
-					panic(fmt.Errorf("column map skips all columns in CSV resource"))
-				case ps == 0:
-				case vs == ps:
-				case vs > 0:
-					panic(fmt.Errorf("missing %d column(s) in CSV resource", ps-vs))
-				case head:
-					panic(fmt.Errorf("column map incompatible with CSV resource"))
-				case len(uc) < len(pc):
-					panic(fmt.Errorf("%d conflicting column(s) in map provided for CSV resource", len(pc)-len(uc)))
-				default:
-					vcols = pc
-					continue
-				}
-
-			default:
-				if b, sl := io.SliceCSV(ln, res.Sep, wid); len(sl)-1 == wid {
-					m := make(map[string]string, len(vcols))
-					skip, head = false, true
-					for h, c := range vcols {
-						fs := b[sl[c.col-1]:sl[c.col]]
-						f := *(*string)(unsafe.Pointer(&fs)) // avoid new string for ~8% perf gain
-						if len(c.prefix) > 0 {
-							for _, p := range c.prefix {
-								if skip = strings.HasPrefix(f, p) == !c.inclusive; skip == !c.inclusive {
-									break
-								}
-							}
-							if skip {
-								break
-							} else if c.skip {
-								continue
-							}
-						}
-						if len(f) > 0 {
-							m[h], head = f, head && f == h
-						} else {
-							head = false
-						}
-					}
-					if !skip && !head && len(m) > 0 {
-						m["~line"] = strconv.Itoa(line)
-						select {
-						case res.out <- m:
-						case <-res.sig:
-							return
-						}
-					}
-				} else if algn++; line > 200 && float64(algn)/float64(line) > 0.02 {
-					panic(fmt.Errorf("excessive column misalignment in CSV resource (>%d rows)", algn))
-				}
-			}
-			break
-		}
-	}
-}
-
-// getFixed method on Resource reads fixed-field rows, writing them to "out" channel once converted
-// into key-value maps as specified in Cols until fixed-field input is exhausted or "sig" indicates
-// a halt
-func (res *Resource) getFixed() {
-	head, cols, sel, wid, line, algn := res.Heading, map[string]cmapItem{}, 0, 0, 0, 0
-	for ln := range res.in {
-		for line++; ; {
-			switch {
-			case len(strings.TrimLeft(ln, " ")) == 0:
-			case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang):
-				select {
-				case res.out <- map[string]string{"~meta": ln[len(res.Shebang):], "~line": strconv.Itoa(line)}:
-				case <-res.sig:
-					return
-				}
-			case res.Comment != "" && strings.HasPrefix(ln, res.Comment):
-			case head:
-				head = false
-			case wid == 0:
-				wid = len(ln)
-				if cols, sel = parseCMap(res.Cols, true, wid); sel == 0 {
-					panic(fmt.Errorf("no columns selected by map provided for fixed-field resource"))
-				}
-				continue
-
-			case len(ln) != wid:
-				if algn++; line > 200 && float64(algn)/float64(line) > 0.02 {
-					panic(fmt.Errorf("excessive column misalignment in fixed-field resource (>%d rows)", algn))
-				}
-			default:
-				m, skip := make(map[string]string, len(cols)), false
-				for h, c := range cols {
-					f := strings.TrimSpace(ln[c.begin-1 : c.col])
-					if len(c.prefix) > 0 {
-						for _, p := range c.prefix {
-							if skip = strings.HasPrefix(f, p) == !c.inclusive; skip == !c.inclusive {
-								break
-							}
-						}
-						if skip {
-							break
-						} else if c.skip {
-							continue
-						}
-					}
-					if len(f) > 0 {
-						m[h] = f
-					}
-				}
-				if !skip && len(m) > 0 {
-					m["~line"] = strconv.Itoa(line)
-					select {
-					case res.out <- m:
-					case <-res.sig:
-						return
-					}
-				}
-			}
-			break
-		}
-	}
-}
-
-// findSpec method on Resource scans settings cache for CSV resource specifier signature matching
-// resource, returning specifier if found
-//   CSV resource type format specifier syntax:
-// 		"=<sep><cols>[,<col>[$<len>][:<pfx>[:<pfx>]...]]..." (column lengths/prefixes)
-//		"=<sep>{<head>[,<head>]...}" (column heads uniquely identifying format)
-//   examples:
-// 		"=|35,7:INTL:DOM,12$13:20,21$3"
-//		"=,120,102$16,17$3:Mon:Tue:Wed:Thu:Fri:Sat:Sun,62$5:S :M :L :XL"
-//		"=,{name,age,account number}"
-func (res *Resource) findSpec() (spec string) {
-	head := make(map[string]int, len(res.Split[0]))
-	for _, c := range res.Split[0] {
-		head[c]++
-	}
-nextSpec:
-	for _, spec = range res.SettingsCache.GetSpecs() {
-		if !strings.HasPrefix(spec, "="+string(res.Sep)) {
-			continue nextSpec
-		}
-		switch spec[2] {
-		case '{':
-			for _, s := range strings.Split(strings.Trim(spec[2:], "{}"), ",") {
-				if _, ok := head[strings.TrimSpace(s)]; !ok {
-					continue nextSpec
-				}
-			}
-			return
-		default:
-			for _, s := range res.Split {
-			nextTerm:
-				for i, t := range strings.Split(spec[2:], ",") {
-					v, c1, c2 := strings.Split(t, ":"), -1, -1
-					switch cv := strings.Split(v[0], "$"); len(cv) {
-					default:
-						c2 = atoi(strings.TrimSpace(cv[1]), -1)
-						fallthrough
-					case 1:
-						c1 = atoi(strings.TrimSpace(cv[0]), -1)
-					}
-					switch {
-					case i == 0 && c1 != len(s):
-						continue nextSpec
-					case i == 0 || c1 <= 0:
-						continue nextTerm
-					case c1 > len(s) || c2 >= 0 && c2 != len(s[c1-1]):
-						continue nextSpec
-					case len(v) == 1:
-						continue nextTerm
-					}
-					for _, pfx := range v[1:] {
-						if strings.HasPrefix(s[c1-1], strings.TrimLeft(pfx, " ")) {
-							continue nextTerm
-						}
-					}
-					continue nextSpec
-				}
-			}
-			return
-		}
-	}
-	return ""
-}
-
-// findFSpec method on Resource scans format cache for fixed-field resource specifier signature
-// matching resource, returning specifier and heading indicator if found
-//   fixed-field TXT resource type specifier syntax (heading/field lengths/prefixes):
-// 		"=(f|h)(<cols>|(<col>:<pfx>[:<pfx>]...))[,(f|h)(<cols>|(<col>:<pfx>[:<pfx>]...))]..."
-//   examples:
-//		"=h80,h1:HEAD01,f132,f52:20,f126:S :M :L :XL" (heading & field row lengths/prefixes)
-//		"=f72,f72:T:F,f20:SKU" (field row length/prefixes only)
-func (res *Resource) findFSpec() (spec string, head bool) {
-nextSpec:
-	for _, spec := range res.SettingsCache.GetSpecs() {
-		if !strings.HasPrefix(spec, "=h") && !strings.HasPrefix(spec, "=f") {
-			continue nextSpec
-		}
-		for i, p := range res.Preview {
-		nextTerm:
-			for _, t := range strings.Split(spec[1:], ",") {
-				v := strings.Split(strings.TrimLeft(t, " "), ":")
-				c := atoi(strings.TrimLeft(strings.TrimRight(v[0], " "), "hf"), -1)
-				switch {
-				case v[0] == "" || c <= 0 || !strings.ContainsAny(v[0][0:1], "hf"):
-					continue nextTerm
-				case (v[0][0] == 'h' && i == 0 || v[0][0] == 'f' && i > 0) && len(v) == 1 && c != len(p):
-					continue nextSpec
-				case len(v) == 1 || v[0][0] == 'h' && i > 0 || v[0][0] == 'f' && i == 0:
-					continue nextTerm
-				}
-				for _, pfx := range v[1:] {
-					if strings.HasPrefix(p[c-1:], pfx) {
-						continue nextTerm
-					}
-				}
-				continue nextSpec
-			}
-		}
-		return spec, res.Heading || strings.HasPrefix(spec, "=h") || strings.Contains(spec, ",h")
-	}
-	return "", res.Heading
-}
-
-// getHeads method on Resource returns a column heads slice in lexical order from column map Col
-// or from the resource itself if a CSV type
-func (res *Resource) getHeads() (heads []string) {
-	var th []string
-	if res.Cols == "" && res.Typ == RTcsv && res.Heading {
-		th = res.Split[0]
-	} else if res.Cols == "" {
-		return
-	} else {
-		for _, t := range strings.Split(res.Cols, ",") {
-			if h := strings.Split(t, ":")[0]; h != "" && !strings.HasPrefix(h, "!") {
-				th = append(th, h)
-			}
-		}
-	}
-	m := make(map[string]bool)
-	for _, h := range th {
-		if !m[h] {
-			heads = append(heads, h)
-			m[h] = true
-		}
-	}
-	return
-}
-
-// parseCMap parses a column-map string for CSV or fixed-field resource types of specified width,
-// returning map with selected column count
-//   column-map syntax for CSV resource types:
-//		"[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<col>]
-//		 [,[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<col>]]..."
-//   column-map syntax for fixed-field TXT resource types:
-//		"[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<bcol>]:<ecol>
-//		 [,[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<bcol>]:<ecol>]..."
-//   examples (in shell use, enclose in single-quotes):
-//		"name,,,age,,acct num" (implicit columns, with skips)
-//		"name:1,age:4,acct num:6" (same, with explicit columns)
-//		"name:={James:Mary},,,age,,acct num:!{N/A:00000}" (same with inclusive/exclusive filters)
-//		"name:20,:62,age:65,:122,acct num:127" (now in a fixed file with implicit begin columns)
-//		"name:1:20,age:63:65,!acct num:!{N/A:00000:}:123:127" (same but explicit with skip/filter)
-func parseCMap(cmap string, fixed bool, wid int) (m map[string]cmapItem, selected int) {
-	switch {
-	case cmap == "" && fixed:
-		return map[string]cmapItem{"~raw": {begin: 1, col: wid}}, 1
-	case cmap == "":
-		return
-	}
-	m = make(map[string]cmapItem, 32)
-	cursor := 0
-
-	for _, t := range strings.Split(cmap, ",") {
-		v, mi, a, b, h := strings.Split(t, ":"), cmapItem{}, 0, 0, ""
-
-		if len(v) > 2 && fixed {
-			if mi.col, b = atoi(v[len(v)-1], 0), len(v)-1; mi.col > 0 {
-				if mi.begin, b = atoi(v[len(v)-2], 0), len(v)-3; mi.begin == 0 || mi.begin > mi.col {
-					mi.begin, b = 0, len(v)-2
-				}
-			}
-		} else if len(v) > 1 {
-			if mi.col, b = atoi(v[len(v)-1], 0), len(v)-2; mi.col == 0 {
-				b = len(v) - 1
-			}
-		}
-		for a = 1; a < len(v) && !strings.HasPrefix(v[a], "={") && !strings.HasPrefix(v[a], "!{"); a++ {
-		}
-		if a <= b && strings.HasSuffix(v[b], "}") {
-			mi.skip, mi.inclusive = strings.HasPrefix(v[0], "!"), v[a][0] == '='
-			v[a] = v[a][2:]
-			v[b] = v[b][:len(v[b])-1]
-			mi.prefix = v[a : b+1]
-		} else if a < len(v) {
-			continue
-		}
-		switch {
-		case a <= b:
-			h = strings.Join(v[:a], ":")
-		case mi.begin > 0:
-			h = strings.Join(v[:len(v)-2], ":")
-		case mi.col > 0:
-			h = strings.Join(v[:len(v)-1], ":")
-		default:
-			h = t
-		}
-		switch {
-		case fixed && mi.col > cursor && mi.begin == 0:
-			mi.begin = cursor + 1
-		case !fixed && mi.col == 0:
-			mi.col = cursor + 1
-		}
-
-		switch th := strings.TrimLeft(h, "!"); {
-		case mi.col == 0 || mi.col > wid || mi.begin == 0 && fixed:
-			continue
-		case th != "" && (h[0] != '!' || mi.skip):
-			m[th] = mi
-			fallthrough
-		default:
-			cursor = mi.col
-		}
-	}
-
-	for _, me := range m {
-		if !me.skip {
-			selected++
-		}
-	}
-	return
-}
-
-// atoi is a helper string-to-int function with selectable default value on error
-func atoi(s string, d int) int {
-	i, e := strconv.Atoi(s)
-	if e != nil {
-		return d
-	}
-	return i
-}
+package csv
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	stdio "io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/sententico/cost/internal/io"
+)
+
+type (
+	// resStat Resource state (see const)
+	resStat uint8
+
+	// colConv column type converter
+	colConv func(string) (interface{}, error)
+
+	// typeConv column type converters by column head
+	typeConv map[string]colConv
+
+	// wcol Writer layout column
+	wcol struct {
+		head string   // column head
+		item cmapItem // column map item
+	}
+
+	// cmapItem ...
+	cmapItem struct {
+		col, begin      int                 // column reference (paired with begin column for fixed-field files)
+		skip, inclusive bool                // skip: filter-only columns; inclusive: prefix op (exclusive default)
+		prefix          []string            // prefix operands
+		tests           []func(string) bool // regex, numeric range and additional prefix filters
+	}
+)
+
+// Resource states
+const (
+	rsNIL    resStat = iota // initial state
+	rsOPEN                  // resource opened, peek-ahead completed
+	rsGET                   // channels opened to get resource contents
+	rsCLOSED                // resource closed (cannot be re-opened)
+)
+
+// general package constants
+const (
+	previewLines = 24       // maximum preview lines returned in Resource on Open (must be >2)
+	sepSet       = ",\t|;:" // priority of separator runes automatically checked if none specified
+	bigFieldLen  = 36       // mean field length above which CSV column-density is suspiciously low
+	matchMin     = 0.8      // default minimum column similarity of compatible ResourceSet members
+
+	lockWait  = 10 * time.Second      // maximum wait for settings lock
+	lockPoll  = 50 * time.Millisecond // settings lock polling interval
+	lockStale = 2 * time.Minute       // age of settings lock file presumed stale (if not flock)
+)
+
+var (
+	commentSet = [...]string{"#", "//", "'"}
+)
+
+// peekAhead reads initial lines of an opened resource, populating its identification fields. These
+// include a preview slice of raw data rows (excluding blank and comment lines), a total resource
+// row estimate, an indicator whether the first row is a heading, the comment prefix used (if any),
+// and if a CSV type, the field separator with trimmed fields of the preview data rows split by it.
+// A format signature (specifier or heading MD5 hash, as available) with any settings info mapped
+// to it (like file application type, version and default column selector-map) are also provided.
+func (res *Resource) peekAhead() {
+	// TODO: improve override handling (Typ, Sep, Cols, Comment, Shebang)
+	// comment/shebang may not have (") prefix
+	row, fix, tlen, sep, max, hash := -1, 0, 0, '\x00', 1, ""
+nextLine:
+	for ln := range res.peek {
+		switch {
+		case len(strings.TrimSpace(ln)) == 0:
+		case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang) || res.Comment != "" && strings.HasPrefix(ln, res.Comment):
+			if row < 0 {
+				row = 0
+			}
+		case row < 0:
+			row = 0
+			for _, p := range commentSet {
+				if strings.HasPrefix(ln, p) {
+					res.Comment, res.Shebang = p, p+"!"
+					continue nextLine
+				}
+			}
+			fallthrough
+		default:
+			switch row++; {
+			case row == 2:
+				fix = utf8.RuneCountInString(ln)
+			case utf8.RuneCountInString(ln) != fix:
+				fix = 0
+			}
+			tlen += len(ln)
+			res.Preview = append(res.Preview, ln)
+		}
+	}
+	switch {
+	case len(res.ierr) > 0:
+		panic(fmt.Errorf("problem peeking ahead on resource (%v)", <-res.ierr))
+	case row < 1:
+		res.Typ, res.Rows = RTempty, 0
+		return
+	case res.Typ == RTxlsx:
+		res.Rows = -1
+	case res.finfo != nil && res.Codec == "" && strings.HasPrefix(res.Encoding, "utf-16"):
+		res.Rows = int(float64(res.finfo.Size())/float64(2*(tlen-len(res.Preview[0])+row-1))*0.995+0.5) * (row - 1)
+	case res.finfo != nil && res.Codec == "":
+		res.Rows = int(float64(res.finfo.Size())/float64(tlen-len(res.Preview[0])+row-1)*0.995+0.5) * (row - 1)
+	default:
+		res.Rows = -1
+	}
+
+nextSep:
+	for _, r := range string(res.Sep) + sepSet {
+		c, sl, sh := 0, []string{}, []string{}
+		for _, ln := range res.Preview {
+			if sl = io.SplitCSV(ln, r); len(sl) <= max || c > 0 && len(sl) != c {
+				continue nextSep
+			}
+			if c == 0 {
+				c, sh = len(sl), sl
+			}
+		}
+		if sep, max, hash = r, c, fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(sh, string(r))))); res.SettingsCache.Find(hash) {
+			break
+		}
+		qh := make(map[string]int, c)
+		for _, h := range sh {
+			h = strings.TrimSpace(h)
+			if _, e := strconv.ParseFloat(h, 64); e != nil && len(h) > 0 {
+				qh[h]++
+			}
+		}
+		if len(qh) != c {
+			hash = ""
+		}
+		if sep == res.Sep {
+			break
+		}
+	}
+
+	switch res.Sep = sep; {
+	case (res.Typ == RTunk || res.Typ == RTjsonl) && res.peekJSONL():
+		// JSON Lines resource type
+		res.Typ, res.Sep, res.Heading = RTjsonl, '\x00', false
+	case res.Sep == '\x00' && fix == 0 && res.Typ != RTxlsx:
+		// unknown resource type
+		res.Typ = RTunk
+	case res.Sep != '\x00' && fix != 0 && (res.Typ == RTfixed || res.Typ == RTunk && (hash == "" || fix/max > bigFieldLen)):
+		// ambigious resource type, but evidence for CSV is weak
+		fallthrough
+	case res.Sep == '\x00' && res.Typ != RTxlsx:
+		// fixed-field resource type
+		res.Typ, res.Heading = RTfixed, res.Heading || utf8.RuneCountInString(res.Preview[0]) != fix
+		res.Sig, res.Heading = res.findFSpec()
+	default:
+		// CSV resource type (XLSX worksheets rendered as comma-separated CSV)
+		if res.Typ == RTxlsx {
+			res.Sep = ','
+		} else {
+			res.Typ = RTcsv
+		}
+		for _, r := range res.Preview {
+			res.Split = append(res.Split, io.SplitCSV(r, res.Sep))
+		}
+		if res.Sig, res.Heading = hash, res.Heading || hash != ""; !res.SettingsCache.Find(res.Sig) {
+			if spec := res.findSpec(); spec != "" {
+				res.Sig, res.Heading = spec, spec[2] == '{'
+			}
+		}
+	}
+	res.applySettings()
+}
+
+// applySettings method on Resource applies format Settings matched to Sig, or to the nearest
+// format if at least MatchMin similar (otherwise only reported in Nearest), and sets column heads
+func (res *Resource) applySettings() {
+	if res.SettingsCache != nil {
+		if res.Settings, res.Nearest = res.SettingsCache.Get(res.Sig), nil; !res.SettingsCache.Find(res.Sig) {
+			if res.Nearest = res.SettingsCache.Nearest(res.Columns()); res.Nearest != nil && res.MatchMin > 0 &&
+				res.Nearest.Score >= res.MatchMin {
+				res.Settings, res.Nearest.Applied = res.SettingsCache.Get(res.Nearest.Sig), true
+			}
+		}
+		if res.Cols == "" {
+			res.Cols = res.Settings.Cols
+		}
+		if res.Types == nil {
+			res.Types = res.Settings.Types
+		}
+	}
+	res.Heads = res.getHeads()
+}
+
+// resume method on Resource positions an opened resource for Get to continue following its Resume
+// checkpoint, re-applying any heading record; uncompressed UTF-8 CSV or fixed-field content of
+// "file" is re-read from the checkpoint offset, while other content is skipped through it (Parquet
+// row groups are skipped by getParquet)
+func (res *Resource) resume(file *os.File) {
+	ck := res.Resume
+	switch {
+	case ck.Sig != res.Sig:
+		panic(fmt.Errorf("checkpoint format signature %q does not match resource (%q)", ck.Sig, res.Sig))
+	case res.Typ == RTparquet:
+		return
+	}
+	var pre []io.Rec
+	if res.Heading {
+		for rec := range res.in {
+			switch {
+			case len(strings.TrimSpace(rec.Text)) == 0:
+			case res.Shebang != "" && strings.HasPrefix(rec.Text, res.Shebang):
+			case res.Comment != "" && strings.HasPrefix(rec.Text, res.Comment):
+			default:
+				pre = append(pre, rec)
+			}
+			if len(pre) > 0 {
+				break
+			}
+		}
+	}
+
+	src, isig, line, off, skip := res.in, res.isig, 0, int64(0), ck.Off
+	if file != nil && (res.Typ == RTcsv || res.Typ == RTfixed) && res.Codec == "" && res.Encoding == "utf-8" {
+		close(res.isig) // halt reading from start of file before seeking
+		for res.isig = nil; ; {
+			if _, ok := <-src; !ok {
+				break
+			}
+		}
+		bom := make([]byte, 3)
+		if n, _ := file.ReadAt(bom, 0); n < 3 || string(bom) != "\xef\xbb\xbf" {
+			bom = bom[:0]
+		}
+		if _, e := file.Seek(int64(len(bom))+ck.Off, 0); e != nil {
+			panic(fmt.Errorf("cannot seek to checkpoint (%v)", e))
+		}
+		r, _, e := io.Decode(file, "utf-8")
+		if e != nil {
+			panic(e)
+		}
+		res.reader = r
+		_, src, res.ierr, isig = res.readRec(r, 0)
+		line, off, skip = ck.Line, ck.Off, -1
+	}
+
+	in, sig := make(chan io.Rec, 64), make(chan int)
+	go func() {
+		defer close(isig)
+		defer close(in)
+		for _, rec := range pre {
+			in <- rec
+		}
+		for rec := range src {
+			if rec.End <= skip {
+				select {
+				case <-sig:
+					return
+				default:
+					continue
+				}
+			}
+			rec.Line, rec.Off, rec.End = rec.Line+line, rec.Off+off, rec.End+off
+			select {
+			case in <- rec:
+			case <-sig:
+				return
+			}
+		}
+	}()
+	res.in, res.isig = in, sig
+}
+
+// getCSV method on Resource reads CSV rows (logical records possibly spanning lines), writing them
+// to "out" channel once converted into key-value maps as specified in Cols until CSV input is
+// exhausted or "sig" indicates a halt
+func (res *Resource) getCSV() {
+	var vcols map[string]cmapItem
+	wid := 0
+	for rec := range res.in {
+		for ln := rec.Text; ; {
+			switch {
+			case len(strings.TrimSpace(ln)) == 0:
+			case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang):
+				select {
+				case res.out <- res.mark(map[string]string{"~meta": ln[len(res.Shebang):]}, rec.Line, rec.Text, rec.End):
+				case <-res.sig:
+					return
+				}
+			case res.Comment != "" && strings.HasPrefix(ln, res.Comment):
+			case len(vcols) == 0:
+				var data bool
+				if vcols, wid, data = res.csvCols(ln); data {
+					continue
+				}
+
+			default:
+				m, re := res.csvRow(rec, vcols, wid)
+				if re != nil && !res.rowErr(re, "CSV") {
+					return
+				} else if m != nil {
+					select {
+					case res.out <- m:
+					case <-res.sig:
+						return
+					}
+				}
+			}
+			break
+		}
+	}
+}
+
+// csvCols method on Resource returns CSV column map items by column head with field count from the
+// heading (or first data) row "ln", and whether "ln" is a data row
+func (res *Resource) csvCols(ln string) (vcols map[string]cmapItem, wid int, data bool) {
+	sl, uc, vs := io.SplitCSV(ln, res.Sep), make(map[int]int), 0
+	vcols, wid = make(map[string]cmapItem, 32), len(sl)
+	pc, ps := parseCMap(res.Cols, false, wid)
+	for _, c := range pc {
+		uc[c.col]++
+	}
+	for i, h := range sl {
+		if h != "" && (ps == 0 || pc[h].col > 0) {
+			c := pc[h]
+			c.col = i + 1
+			vcols[h] = c
+		}
+	}
+	for _, c := range vcols {
+		if !c.skip {
+			vs++
+		}
+	}
+	switch {
+	case ps == 0 && (!res.Heading || len(vcols) != wid):
+		panic(fmt.Errorf("can't read CSV resource without internal column heads or map"))
+	case ps == 0 && vs == 0:
+		panic(fmt.Errorf("column map skips all columns in CSV resource"))
+	case ps == 0:
+	case vs == ps:
+	case vs > 0:
+		panic(fmt.Errorf("missing %d column(s) in CSV resource", ps-vs))
+	case res.Heading:
+		panic(fmt.Errorf("column map incompatible with CSV resource"))
+	case len(uc) < len(pc):
+		panic(fmt.Errorf("%d conflicting column(s) in map provided for CSV resource", len(pc)-len(uc)))
+	default:
+		return pc, wid, true
+	}
+	return
+}
+
+// csvRow method on Resource returns a key-value map for CSV record "rec" of "wid" fields selected
+// and filtered by "vcols" (nil if excluded or a repeated heading), or a RowError if malformed
+func (res *Resource) csvRow(rec io.Rec, vcols map[string]cmapItem, wid int) (map[string]string, *RowError) {
+	b, sl := io.SliceCSV(rec.Text, res.Sep, wid)
+	if len(sl)-1 != wid {
+		return nil, &RowError{Line: rec.Line, Off: rec.Off, Raw: rec.Text,
+			Reason: fmt.Sprintf("%d fields (%d expected)", len(sl)-1, wid)}
+	}
+	m, skip, head := make(map[string]string, len(vcols)), false, true
+	for h, c := range vcols {
+		fs := b[sl[c.col-1]:sl[c.col]]
+		f := *(*string)(unsafe.Pointer(&fs)) // avoid new string for ~8% perf gain
+		if c.filtered() {
+			if skip = !c.pass(f); skip {
+				break
+			} else if c.skip {
+				continue
+			}
+		}
+		if len(f) > 0 {
+			m[h], head = f, head && f == h
+		} else {
+			head = false
+		}
+	}
+	if skip || head || len(m) == 0 || !res.derive(m) {
+		return nil, nil
+	}
+	res.mark(m, rec.Line, rec.Text, rec.End)
+	return m, nil
+}
+
+// getFixed method on Resource reads fixed-field rows, writing them to "out" channel once converted
+// into key-value maps as specified in Cols until fixed-field input is exhausted or "sig" indicates
+// a halt
+func (res *Resource) getFixed() {
+	head, cols, sel, wid := res.Heading, map[string]cmapItem{}, 0, 0
+	for rec := range res.in {
+		for ln, line := rec.Text, rec.Line; ; {
+			switch {
+			case len(strings.TrimLeft(ln, " ")) == 0:
+			case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang):
+				select {
+				case res.out <- res.mark(map[string]string{"~meta": ln[len(res.Shebang):]}, line, rec.Text, rec.End):
+				case <-res.sig:
+					return
+				}
+			case res.Comment != "" && strings.HasPrefix(ln, res.Comment):
+			case head:
+				head = false
+			case wid == 0:
+				wid = utf8.RuneCountInString(ln)
+				if cols, sel = parseCMap(res.Cols, true, wid); sel == 0 {
+					panic(fmt.Errorf("no columns selected by map provided for fixed-field resource"))
+				}
+				continue
+
+			case utf8.RuneCountInString(ln) != wid:
+				if !res.rowErr(&RowError{Line: line, Off: rec.Off, Raw: ln,
+					Reason: fmt.Sprintf("%d columns (%d expected)", utf8.RuneCountInString(ln), wid)}, "fixed-field") {
+					return
+				}
+			default:
+				m, skip, rl := make(map[string]string, len(cols)), false, []rune(nil)
+				if len(ln) != wid {
+					rl = []rune(ln) // multi-byte runes require rune column slicing
+				}
+				for h, c := range cols {
+					var f string
+					if rl != nil {
+						f = strings.TrimSpace(string(rl[c.begin-1 : c.col]))
+					} else {
+						f = strings.TrimSpace(ln[c.begin-1 : c.col])
+					}
+					if c.filtered() {
+						if skip = !c.pass(f); skip {
+							break
+						} else if c.skip {
+							continue
+						}
+					}
+					if len(f) > 0 {
+						m[h] = f
+					}
+				}
+				if !skip && len(m) > 0 && res.derive(m) {
+					res.mark(m, line, rec.Text, rec.End)
+					select {
+					case res.out <- m:
+					case <-res.sig:
+						return
+					}
+				}
+			}
+			break
+		}
+	}
+}
+
+// rowErr method on Resource handles a malformed row of resource type "typ" as specified by its
+// ErrPolicy, returning false if "sig" indicates a halt
+func (res *Resource) rowErr(re *RowError, typ string) bool {
+	switch res.nerr++; res.ErrPolicy {
+	case EPcollect, EPabort:
+		select {
+		case res.rerr <- re:
+		case <-res.sig:
+			return false
+		}
+		if res.ErrPolicy == EPabort && res.nerr > res.ErrLimit {
+			panic(fmt.Errorf("malformed rows in %s resource exceed limit (%d)", typ, res.ErrLimit))
+		}
+	default:
+		if re.Line > 200 && float64(res.nerr)/float64(re.Line) > 0.02 {
+			panic(fmt.Errorf("excessive column misalignment in %s resource (>%d rows)", typ, res.nerr))
+		}
+	}
+	return true
+}
+
+// readRec method on Resource returns channels (as for io.ReadRec) of logical records read from
+// "r" with "peek" lines of peek-ahead; JSON Lines content is read as unjoined lines
+func (res *Resource) readRec(r stdio.Reader, peek int) (<-chan string, <-chan io.Rec, <-chan error, chan<- int) {
+	if res.lines {
+		return io.ReadLnRec(r, peek)
+	}
+	return io.ReadRec(r, peek, sepSet+strings.TrimLeft(string(res.Sep), "\x00"))
+}
+
+// jsonHead returns true if the first non-blank line buffered in "br" is a JSON object
+func jsonHead(br *bufio.Reader) bool {
+	b, _ := br.Peek(br.Size())
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			i = len(b)
+		}
+		if ln := strings.TrimSpace(string(b[:i])); ln != "" {
+			_, e := flattenJSON(ln)
+			return strings.HasPrefix(ln, "{") && e == nil
+		}
+		if i == len(b) {
+			break
+		}
+		b = b[i+1:]
+	}
+	return false
+}
+
+// getJSONL method on Resource reads JSON Lines rows, writing them to "out" channel once flattened
+// into key-value maps as specified in Cols until input is exhausted or "sig" indicates a halt
+func (res *Resource) getJSONL() {
+	cols, _ := parseCMap(res.Cols, false, 0xffff)
+	for rec := range res.in {
+		ln, line := strings.TrimSpace(rec.Text), rec.Line
+		if ln == "" {
+			continue
+		}
+		flat, e := flattenJSON(ln)
+		if e != nil {
+			if !res.rowErr(&RowError{Line: line, Off: rec.Off, Raw: rec.Text, Reason: e.Error()}, "JSON Lines") {
+				return
+			}
+			continue
+		}
+		if !res.putRow(cols, flat, line, rec.End) {
+			return
+		}
+	}
+}
+
+// mark method on Resource returns row "m" marked with its source "line" and, if Checkpoints are
+// requested, the content offset "off" following it (with source lines spanned by record "text",
+// if more than one)
+func (res *Resource) mark(m map[string]string, line int, text string, off int64) map[string]string {
+	if m["~line"] = strconv.Itoa(line); res.Checkpoints {
+		m["~off"] = strconv.FormatInt(off, 10)
+		if n := strings.Count(text, "\n"); n > 0 {
+			m["~lines"] = strconv.Itoa(n + 1)
+		}
+	}
+	return m
+}
+
+// putRow method on Resource sends flattened row "flat" (selected and filtered by column map
+// "cols") from source "line" ending at offset "off" to the Get consumer, returning false if the
+// resource is closed
+func (res *Resource) putRow(cols map[string]cmapItem, flat map[string]string, line int, off int64) bool {
+	m, skip := make(map[string]string, len(flat)), false
+	if len(cols) == 0 {
+		for h, f := range flat {
+			if len(f) > 0 {
+				m[h] = f
+			}
+		}
+	} else {
+		for h, c := range cols {
+			f := flat[h]
+			if skip = !c.pass(f); skip {
+				break
+			} else if !c.skip && len(f) > 0 {
+				m[h] = f
+			}
+		}
+	}
+	if !skip && len(m) > 0 && res.derive(m) {
+		res.mark(m, line, "", off)
+		select {
+		case res.out <- m:
+		case <-res.sig:
+			return false
+		}
+	}
+	return true
+}
+
+// peekJSONL method on Resource returns true if the first and most other Preview rows are JSON
+// objects, setting format signature (MD5 hash of flattened key set) and splitting Preview rows
+// by key
+func (res *Resource) peekJSONL() bool {
+	var rows []map[string]string
+	keys, bad := make(map[string]bool), 0
+	for i, ln := range res.Preview {
+		if ln = strings.TrimSpace(ln); !strings.HasPrefix(ln, "{") && i == 0 {
+			return false
+		}
+		flat, e := flattenJSON(ln)
+		switch {
+		case e == nil:
+		case i == 0 || bad*4 >= len(res.Preview):
+			return false
+		default:
+			bad++
+			continue
+		}
+		for k := range flat {
+			keys[k] = true
+		}
+		rows = append(rows, flat)
+	}
+
+	res.jkeys = make([]string, 0, len(keys))
+	for k := range keys {
+		res.jkeys = append(res.jkeys, k)
+	}
+	sort.Strings(res.jkeys)
+	res.Split = nil
+	for _, flat := range rows {
+		sl := make([]string, 0, len(res.jkeys))
+		for _, k := range res.jkeys {
+			sl = append(sl, flat[k])
+		}
+		res.Split = append(res.Split, sl)
+	}
+	res.Sig = fmt.Sprintf("%x", md5.Sum([]byte("{"+strings.Join(res.jkeys, ",")+"}")))
+	return true
+}
+
+// flattenJSON returns key-value map of a JSON object with nested values keyed by dotted paths
+func flattenJSON(obj string) (map[string]string, error) {
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(obj))
+	d.UseNumber()
+	if e := d.Decode(&v); e != nil {
+		return nil, fmt.Errorf("invalid JSON (%v)", e)
+	} else if _, ok := v.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("JSON value not an object")
+	}
+	flat := make(map[string]string)
+	var walk func(string, interface{})
+	walk = func(p string, v interface{}) {
+		switch tv := v.(type) {
+		case map[string]interface{}:
+			for k, e := range tv {
+				if p != "" {
+					k = p + "." + k
+				}
+				walk(k, e)
+			}
+		case []interface{}:
+			for i, e := range tv {
+				walk(p+"."+strconv.Itoa(i), e)
+			}
+		case string:
+			flat[p] = tv
+		case json.Number:
+			flat[p] = tv.String()
+		case bool:
+			flat[p] = strconv.FormatBool(tv)
+		case nil:
+			flat[p] = ""
+		}
+	}
+	walk("", v)
+	return flat, nil
+}
+
+// findSpec method on Resource scans settings cache for CSV resource specifier signature matching
+// resource, returning specifier if found
+//   CSV resource type format specifier syntax:
+// 		"=<sep><cols>[,<col>[$<len>][:<pfx>[:<pfx>]...]]..." (column lengths/prefixes)
+//		"=<sep>{<head>[,<head>]...}" (column heads uniquely identifying format)
+//   examples:
+// 		"=|35,7:INTL:DOM,12$13:20,21$3"
+//		"=,120,102$16,17$3:Mon:Tue:Wed:Thu:Fri:Sat:Sun,62$5:S :M :L :XL"
+//		"=,{name,age,account number}"
+func (res *Resource) findSpec() (spec string) {
+	head := make(map[string]int, len(res.Split[0]))
+	for _, c := range res.Split[0] {
+		head[c]++
+	}
+nextSpec:
+	for _, spec = range res.SettingsCache.GetSpecs() {
+		if !strings.HasPrefix(spec, "="+string(res.Sep)) {
+			continue nextSpec
+		}
+		switch spec[2] {
+		case '{':
+			for _, s := range strings.Split(strings.Trim(spec[2:], "{}"), ",") {
+				if _, ok := head[strings.TrimSpace(s)]; !ok {
+					continue nextSpec
+				}
+			}
+			return
+		default:
+			for _, s := range res.Split {
+			nextTerm:
+				for i, t := range strings.Split(spec[2:], ",") {
+					v, c1, c2 := strings.Split(t, ":"), -1, -1
+					switch cv := strings.Split(v[0], "$"); len(cv) {
+					default:
+						c2 = atoi(strings.TrimSpace(cv[1]), -1)
+						fallthrough
+					case 1:
+						c1 = atoi(strings.TrimSpace(cv[0]), -1)
+					}
+					switch {
+					case i == 0 && c1 != len(s):
+						continue nextSpec
+					case i == 0 || c1 <= 0:
+						continue nextTerm
+					case c1 > len(s) || c2 >= 0 && c2 != len(s[c1-1]):
+						continue nextSpec
+					case len(v) == 1:
+						continue nextTerm
+					}
+					for _, pfx := range v[1:] {
+						if strings.HasPrefix(s[c1-1], strings.TrimLeft(pfx, " ")) {
+							continue nextTerm
+						}
+					}
+					continue nextSpec
+				}
+			}
+			return
+		}
+	}
+	return ""
+}
+
+// findFSpec method on Resource scans format cache for fixed-field resource specifier signature
+// matching resource, returning specifier and heading indicator if found
+//   fixed-field TXT resource type specifier syntax (heading/field lengths/prefixes):
+// 		"=(f|h)(<cols>|(<col>:<pfx>[:<pfx>]...))[,(f|h)(<cols>|(<col>:<pfx>[:<pfx>]...))]..."
+//   examples:
+//		"=h80,h1:HEAD01,f132,f52:20,f126:S :M :L :XL" (heading & field row lengths/prefixes)
+//		"=f72,f72:T:F,f20:SKU" (field row length/prefixes only)
+func (res *Resource) findFSpec() (spec string, head bool) {
+nextSpec:
+	for _, spec := range res.SettingsCache.GetSpecs() {
+		if !strings.HasPrefix(spec, "=h") && !strings.HasPrefix(spec, "=f") {
+			continue nextSpec
+		}
+		for i, p := range res.Preview {
+		nextTerm:
+			for _, t := range strings.Split(spec[1:], ",") {
+				v := strings.Split(strings.TrimLeft(t, " "), ":")
+				c := atoi(strings.TrimLeft(strings.TrimRight(v[0], " "), "hf"), -1)
+				switch {
+				case v[0] == "" || c <= 0 || !strings.ContainsAny(v[0][0:1], "hf"):
+					continue nextTerm
+				case (v[0][0] == 'h' && i == 0 || v[0][0] == 'f' && i > 0) && len(v) == 1 && c != len(p):
+					continue nextSpec
+				case len(v) == 1 || v[0][0] == 'h' && i > 0 || v[0][0] == 'f' && i == 0:
+					continue nextTerm
+				}
+				for _, pfx := range v[1:] {
+					if strings.HasPrefix(p[c-1:], pfx) {
+						continue nextTerm
+					}
+				}
+				continue nextSpec
+			}
+		}
+		return spec, res.Heading || strings.HasPrefix(spec, "=h") || strings.Contains(spec, ",h")
+	}
+	return "", res.Heading
+}
+
+// inferFixed method on Resource returns column map and specifier inferred from fixed-field
+// Preview rows (see InferCols)
+func (res *Resource) inferFixed() (cols, spec string) {
+	var rows [][]rune
+	var heading []rune
+	for i, p := range res.Preview {
+		if i == 0 && res.Heading {
+			heading = []rune(p)
+		} else {
+			rows = append(rows, []rune(p))
+		}
+	}
+	wid := 0
+	for _, r := range rows {
+		if len(r) > wid {
+			wid = len(r)
+		}
+	}
+
+	// classify columns: blank in all rows, or with a consistent character class (if any)
+	blank, class := make([]bool, wid), make([]byte, wid)
+	for j := 0; j < wid; j++ {
+		counts, n := map[byte]int{}, 0
+		for _, r := range rows {
+			c := runeClass(r, j)
+			if counts[c]++; c != ' ' {
+				n++
+			}
+		}
+		blank[j] = n == 0
+		for c, k := range counts {
+			if c != ' ' && k*10 >= len(rows)*9 {
+				class[j] = c
+			}
+		}
+	}
+
+	// field boundaries begin after blank columns, or at consistent class transitions
+	var begins []int
+	for j := 0; j < wid; j++ {
+		switch {
+		case blank[j]:
+		case j == 0 || blank[j-1]:
+			begins = append(begins, j)
+		case class[j] != 0 && class[j-1] != 0 && class[j] != class[j-1] && class[j] != '.' && class[j-1] != '.':
+			begins = append(begins, j)
+		}
+	}
+
+	// assign heading words to fields they most overlap
+	words := make([][]string, len(begins))
+	for j := 0; j < len(heading); j++ {
+		if unicode.IsSpace(heading[j]) {
+			continue
+		}
+		k := j
+		for ; k < len(heading) && !unicode.IsSpace(heading[k]); k++ {
+		}
+		best, over := -1, 0
+		for i, b := range begins {
+			e := wid
+			if i+1 < len(begins) {
+				e = begins[i+1]
+			}
+			if o := minInt(k, e) - maxInt(j, b); o > over {
+				best, over = i, o
+			}
+		}
+		if best >= 0 {
+			words[best] = append(words[best], string(heading[j:k]))
+		}
+		j = k
+	}
+
+	var terms, specs []string
+	heads := make(map[string]bool)
+	for i, b := range begins {
+		e := wid
+		if i+1 < len(begins) {
+			e = begins[i+1]
+		}
+		h := strings.Join(words[i], " ")
+		if h = strings.NewReplacer(",", " ", ":", " ").Replace(h); h == "" || heads[h] {
+			h = fmt.Sprintf("c%d", b+1)
+		}
+		heads[h] = true
+		terms = append(terms, fmt.Sprintf("%s:%d:%d", h, b+1, e))
+
+		if v := strings.TrimRight(field(rows[0], b, e), " "); v != "" && len(specs) < 2 && len(rows) > 2 &&
+			!strings.ContainsAny(v, ",:") {
+			same := true
+			for _, r := range rows[1:] {
+				same = same && strings.HasPrefix(field(r, b, len(r)), v)
+			}
+			if same {
+				specs = append(specs, fmt.Sprintf("f%d:%s", b+1, v))
+			}
+		}
+	}
+	if spec = fmt.Sprintf("=f%d", wid); heading != nil {
+		spec = fmt.Sprintf("=h%d,f%d", len(heading), wid)
+	}
+	if len(specs) > 0 {
+		spec += "," + strings.Join(specs, ",")
+	}
+	return strings.Join(terms, ","), spec
+}
+
+// runeClass returns character class of rune "j" of "r": space (or absent), digit ('9'), letter
+// ('A') or punctuation/other ('.')
+func runeClass(r []rune, j int) byte {
+	switch {
+	case j >= len(r) || unicode.IsSpace(r[j]):
+		return ' '
+	case unicode.IsDigit(r[j]):
+		return '9'
+	case unicode.IsLetter(r[j]):
+		return 'A'
+	}
+	return '.'
+}
+
+// field returns runes "b" up to "e" of "r" (bounded by its length, as rows may be ragged)
+func field(r []rune, b, e int) string {
+	return string(r[minInt(b, len(r)):minInt(e, len(r))])
+}
+
+// minInt returns the lesser of "a" and "b"
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt returns the greater of "a" and "b"
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// mergeSettings merges "cache" items Set since last read or written ("dirty") into settings on
+// "disk" (which may have changed since then from "base"), returning merged settings with
+// signatures of unresolved conflicts
+func mergeSettings(disk, base, cache map[string]SettingsItem, dirty map[string]bool) (map[string]SettingsItem, []string) {
+	var conflicts []string
+	for sig := range dirty {
+		mine, ok := cache[sig]
+		if !ok {
+			continue
+		}
+		theirs, onDisk := disk[sig]
+		was, inBase := base[sig]
+		switch {
+		case !onDisk, inBase && sameItem(theirs, was), sameItem(theirs, mine):
+			// no concurrent update (or a concurrent delete)
+			disk[sig] = mine
+		case theirs.Lock != mine.Lock:
+			// locked items prevail over concurrent automatic updates
+			if mine.Lock {
+				disk[sig] = mine
+			}
+		case !mine.Lock && mine.Date.After(theirs.Date):
+			disk[sig] = mine
+		case !mine.Lock && theirs.Date.After(mine.Date):
+		default:
+			conflicts = append(conflicts, sig)
+		}
+	}
+	sort.Strings(conflicts)
+	return disk, conflicts
+}
+
+// sameItem returns true if settings items are equivalent as encoded
+func sameItem(a, b SettingsItem) bool {
+	ja, ea := json.Marshal(a)
+	jb, eb := json.Marshal(b)
+	return ea == nil && eb == nil && string(ja) == string(jb)
+}
+
+// replaceFile atomically replaces file "fn" with content "b" by renaming a temporary file
+func replaceFile(fn string, b []byte) error {
+	f, e := os.CreateTemp(filepath.Dir(fn), "."+filepath.Base(fn)+".*")
+	if e != nil {
+		return e
+	}
+	defer os.Remove(f.Name())
+	if _, e = f.Write(b); e == nil {
+		e = f.Sync()
+	}
+	if ce := f.Close(); e == nil {
+		e = ce
+	}
+	if e == nil {
+		e = os.Chmod(f.Name(), 0644)
+	}
+	if e == nil {
+		e = os.Rename(f.Name(), fn)
+	}
+	return e
+}
+
+// getHeads method on Resource returns a column heads slice in lexical order from column map Col
+// or from the resource itself if a CSV type
+func (res *Resource) getHeads() (heads []string) {
+	var th []string
+	if res.Cols == "" && res.Typ == RTcsv && res.Heading {
+		th = res.Split[0]
+	} else if res.Cols == "" && (res.Typ == RTjsonl || res.Typ == RTparquet) {
+		th = res.jkeys
+	} else if res.Cols == "" {
+		return
+	} else {
+		return cmapHeads(res.Cols)
+	}
+	m := make(map[string]bool)
+	for _, h := range th {
+		if !m[h] {
+			heads = append(heads, h)
+			m[h] = true
+		}
+	}
+	return
+}
+
+// compatible method on Resource returns true if detected resource "r" has a format compatible with
+// that of the resource (see ResourceSet Open)
+func (res *Resource) compatible(r *Resource) bool {
+	switch lim := res.MatchMin; {
+	case res.Typ != r.Typ:
+		return false
+	case res.Sig != "" && res.Sig == r.Sig:
+		return true
+	case res.Settings.Format != "" && res.Settings.Format == r.Settings.Format:
+		return true
+	case lim == 0:
+		lim = matchMin
+		fallthrough
+	default:
+		score, _, _ := similarity(r.Columns(), res.Columns(), false)
+		return score >= lim
+	}
+}
+
+// getMember method on ResourceSet writes rows of opened member "res" (keyed by "~file" and with
+// absent union Heads) to "out" channel and its malformed rows to "rerr" (on a goroutine added to
+// "ewg"), closing the member once exhausted; returns false if the set is closed
+func (set *ResourceSet) getMember(res *Resource, ewg *sync.WaitGroup) bool {
+	defer res.Close()
+	in, err := res.Get()
+	ewg.Add(1)
+	go func() {
+		defer ewg.Done()
+		for re := range res.RowErrs() {
+			re.File = res.Location
+			select {
+			case set.rerr <- re:
+			case <-set.sig:
+				return
+			}
+		}
+	}()
+	for row := range in {
+		if _, ok := row["~meta"]; !ok {
+			for _, h := range set.Heads {
+				if _, ok := row[h]; !ok {
+					row[h] = ""
+				}
+			}
+		}
+		row["~file"] = res.Location
+		select {
+		case set.out <- row:
+		case <-set.sig:
+			return false
+		}
+	}
+	if e := <-err; e != nil {
+		panic(fmt.Errorf("error reading %q: %v", res.Location, e))
+	}
+	return true
+}
+
+// cmapHeads returns unique selected column heads of column map "cmap" in column map order
+func cmapHeads(cmap string) (heads []string) {
+	m := make(map[string]bool)
+	for _, t := range splitBraced(cmap, ',') {
+		if h := splitBraced(t, ':')[0]; h != "" && !strings.HasPrefix(h, "!") && !m[h] {
+			heads = append(heads, h)
+			m[h] = true
+		}
+	}
+	return
+}
+
+// similarity returns a score (0-1) of column heads "a" similarity to format heads "b" by overlap
+// (containment of "b" in "a" if "subset") and relative order, with heads added/removed from "b"
+func similarity(a, b []string, subset bool) (score float64, added, removed []string) {
+	norm := func(h string) string { return strings.ToLower(strings.TrimSpace(h)) }
+	bi := make(map[string]int, len(b))
+	for i, h := range b {
+		bi[norm(h)] = i + 1
+	}
+	var seq []int
+	in := make(map[string]bool, len(a))
+	for _, h := range a {
+		if i := bi[norm(h)]; i > 0 && !in[norm(h)] {
+			seq = append(seq, i)
+		} else if i == 0 {
+			added = append(added, h)
+		}
+		in[norm(h)] = true
+	}
+	for _, h := range b {
+		if !in[norm(h)] {
+			removed = append(removed, h)
+		}
+	}
+	if len(seq) == 0 {
+		return 0, added, removed
+	}
+
+	var lis []int // longest increasing subsequence tails (common heads in the same order)
+	for _, i := range seq {
+		j := sort.SearchInts(lis, i)
+		if j == len(lis) {
+			lis = append(lis, i)
+		} else {
+			lis[j] = i
+		}
+	}
+	overlap, order := float64(len(seq))/float64(len(a)+len(b)-len(seq)), float64(len(lis))/float64(len(seq))
+	if subset {
+		return (0.8*float64(len(seq))/float64(len(b)) + 0.2*order) * 0.9, nil, removed
+	}
+	return 0.8*overlap + 0.2*order, added, removed
+}
+
+// parseCMap parses a column-map string for CSV or fixed-field resource types of specified width,
+// returning map with selected column count
+//   column-map syntax for CSV resource types:
+//		"[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<col>]
+//		 [,[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<col>]]..."
+//   column-map syntax for fixed-field TXT resource types:
+//		"[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<bcol>]:<ecol>
+//		 [,[!]<head>[:(=|!){<pfx>[:<pfx>]...}][:<bcol>]:<ecol>]..."
+//   filters (any number per column; braces may nest, enclosing ',' and ':' as literals):
+//		"={<pfx>[:<pfx>]...}" / "!{...}" (inclusive/exclusive prefix), "~{<regex>}" / "!~{<regex>}"
+//		(regex match/non-match), "#{[<lo>]..[<hi>]}" (numeric range, bounds inclusive)
+//   examples (in shell use, enclose in single-quotes):
+//		"name,,,age,,acct num" (implicit columns, with skips)
+//		"name:1,age:4,acct num:6" (same, with explicit columns)
+//		"name:={James:Mary},,,age,,acct num:!{N/A:00000}" (same with inclusive/exclusive filters)
+//		"name:~{^J(ames|ohn)$},,,age:#{18..65},,acct num:!~{^0+$}" (same with regex/range filters)
+//		"name:20,:62,age:65,:122,acct num:127" (now in a fixed file with implicit begin columns)
+//		"name:1:20,age:63:65,!acct num:!{N/A:00000:}:123:127" (same but explicit with skip/filter)
+//   derived columns ("<head>:@{<expr>}", see parseDerived) are excluded from the returned map;
+//   malformed regex or range filters panic
+func parseCMap(cmap string, fixed bool, wid int) (m map[string]cmapItem, selected int) {
+	switch {
+	case cmap == "" && fixed:
+		return map[string]cmapItem{"~raw": {begin: 1, col: wid}}, 1
+	case cmap == "":
+		return
+	}
+	m = make(map[string]cmapItem, 32)
+	cursor := 0
+
+	for _, t := range splitBraced(cmap, ',') {
+		v, mi, a, b, h := splitBraced(t, ':'), cmapItem{}, 0, 0, ""
+
+		if len(v) > 2 && fixed {
+			if mi.col, b = atoi(v[len(v)-1], 0), len(v)-1; mi.col > 0 {
+				if mi.begin, b = atoi(v[len(v)-2], 0), len(v)-3; mi.begin == 0 || mi.begin > mi.col {
+					mi.begin, b = 0, len(v)-2
+				}
+			}
+		} else if len(v) > 1 {
+			if mi.col, b = atoi(v[len(v)-1], 0), len(v)-2; mi.col == 0 {
+				b = len(v) - 1
+			}
+		}
+		for a = 1; a < len(v) && !strings.HasSuffix(v[a], "}"); a++ {
+		}
+		switch {
+		case a < len(v) && strings.HasPrefix(v[a], "@{"):
+			continue // derived column (see parseDerived)
+		case a <= b:
+			if !mi.addFilters(v[a : b+1]) {
+				continue
+			}
+			mi.skip = strings.HasPrefix(v[0], "!")
+		case a < len(v):
+			continue
+		}
+		switch {
+		case a <= b:
+			h = strings.Join(v[:a], ":")
+		case mi.begin > 0:
+			h = strings.Join(v[:len(v)-2], ":")
+		case mi.col > 0:
+			h = strings.Join(v[:len(v)-1], ":")
+		default:
+			h = t
+		}
+		switch {
+		case fixed && mi.col > cursor && mi.begin == 0:
+			mi.begin = cursor + 1
+		case !fixed && mi.col == 0:
+			mi.col = cursor + 1
+		}
+
+		switch th := strings.TrimLeft(h, "!"); {
+		case mi.col == 0 || mi.col > wid || mi.begin == 0 && fixed:
+			continue
+		case th != "" && (h[0] != '!' || mi.skip):
+			m[th] = mi
+			fallthrough
+		default:
+			cursor = mi.col
+		}
+	}
+
+	for _, me := range m {
+		if !me.skip {
+			selected++
+		}
+	}
+	return
+}
+
+// pass method on cmapItem returns true if field "f" passes any prefix filter and all other filters
+// of column map item
+func (c cmapItem) pass(f string) bool {
+	for _, t := range c.tests {
+		if !t(f) {
+			return false
+		}
+	}
+	for _, p := range c.prefix {
+		if strings.HasPrefix(f, p) {
+			return c.inclusive
+		}
+	}
+	return len(c.prefix) == 0 || !c.inclusive
+}
+
+// setLayout method on Writer orders output columns from column map Cols (or from Heads), setting
+// Heads to match
+func (wr *Writer) setLayout() {
+	wr.layout, wr.filters, wr.wid = nil, nil, 0
+	if wr.Cols == "" {
+		for i, h := range wr.Heads {
+			wr.layout = append(wr.layout, wcol{head: h, item: cmapItem{col: i + 1, begin: i + 1}})
+		}
+		wr.wid = len(wr.layout)
+		return
+	}
+	m, _ := parseCMap(wr.Cols, wr.Typ == RTfixed, 0xffff)
+	for h, c := range m {
+		switch {
+		case !c.skip:
+			wr.layout = append(wr.layout, wcol{head: h, item: c})
+		case c.filtered():
+			wr.filters = append(wr.filters, wcol{head: h, item: c})
+		}
+		if c.col > wr.wid {
+			wr.wid = c.col
+		}
+	}
+	sort.Slice(wr.layout, func(i, j int) bool {
+		return wr.layout[i].item.col < wr.layout[j].item.col
+	})
+	wr.Heads = make([]string, 0, len(wr.layout)) // caller's Heads not overwritten
+	for _, c := range wr.layout {
+		wr.Heads = append(wr.Heads, c.head)
+	}
+}
+
+// put method on Writer encodes "fields" (ordered as layout columns) into a CSV or fixed-field row
+func (wr *Writer) put(fields []string) (e error) {
+	var b strings.Builder
+	switch wr.Typ {
+	case RTfixed:
+		b.Grow(wr.wid + 1)
+		cur := 0
+		for i, c := range wr.layout {
+			if i >= len(fields) || c.item.begin <= cur {
+				continue
+			}
+			f, w := wr.guard(fields[i]), c.item.col-c.item.begin+1
+			b.WriteString(strings.Repeat(" ", c.item.begin-1-cur))
+			if n := utf8.RuneCountInString(f); n > w {
+				f = string([]rune(f)[:w])
+			} else {
+				f += strings.Repeat(" ", w-n)
+			}
+			b.WriteString(f)
+			cur = c.item.col
+		}
+		b.WriteString(strings.Repeat(" ", wr.wid-cur))
+	default:
+		row, col := make([]string, 0, len(fields)), 0
+		for i, f := range fields {
+			switch {
+			case len(wr.layout) == 0:
+			case i >= len(wr.layout):
+				continue
+			default:
+				for ; col+1 < wr.layout[i].item.col; col++ {
+					row = append(row, wr.quote(""))
+				}
+			}
+			row, col = append(row, wr.quote(wr.guard(f))), col+1
+		}
+		b.WriteString(strings.Join(row, string(wr.Sep)))
+	}
+	b.WriteByte('\n')
+	_, e = wr.w.Write([]byte(b.String()))
+	return
+}
+
+// guard method on Writer prefixes fields that would be interpreted as spreadsheet formulas (if
+// guarding specified); fields leading with "+" or "-" (like numbers, phone numbers or placeholders)
+// are only guarded if followed by formula characters
+func (wr *Writer) guard(f string) string {
+	if !wr.Guard || f == "" {
+		return f
+	}
+	switch f[0] {
+	case '=', '@':
+		return "'" + f
+	case '+', '-':
+		if formula(f[1:]) {
+			return "'" + f
+		}
+	}
+	return f
+}
+
+// formula returns true if "f" (following a leading "+" or "-") would be evaluated as a spreadsheet
+// formula: leading with "=", "@", "+" or "-", or containing a function call, external (DDE)
+// reference or sheet reference
+func formula(f string) bool {
+	if f != "" && strings.IndexByte("=@+-", f[0]) >= 0 {
+		return true
+	}
+	for i := 1; i < len(f); i++ {
+		c := f[i-1] | 0x20
+		switch alpha := c >= 'a' && c <= 'z'; f[i] {
+		case '(', '|':
+			if alpha {
+				return true
+			}
+		case '!':
+			if alpha || f[i-1] >= '0' && f[i-1] <= '9' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// quote method on Writer encloses CSV field in double-quotes (escaping any within) if required
+func (wr *Writer) quote(f string) string {
+	switch {
+	case wr.Quote:
+	case f == "":
+		return f
+	case strings.ContainsRune(f, wr.Sep) || strings.ContainsAny(f, "\"\r\n") || f[0] == ' ' || f[len(f)-1] == ' ':
+	default:
+		return f
+	}
+	return `"` + strings.ReplaceAll(f, `"`, `""`) + `"`
+}
+
+// parseTypes returns column type converters for a column type schema
+func parseTypes(ct ColTypes) (typeConv, error) {
+	conv := make(typeConv, len(ct))
+	for h, t := range ct {
+		switch v := strings.SplitN(t, ":", 2); strings.ToLower(strings.TrimSpace(v[0])) {
+		case "", "str", "string":
+		case "int":
+			conv[h] = func(s string) (interface{}, error) {
+				return strconv.ParseInt(s, 10, 64)
+			}
+		case "dec", "decimal":
+			conv[h] = func(s string) (interface{}, error) {
+				return strconv.ParseFloat(s, 64)
+			}
+		case "time":
+			layout := time.RFC3339
+			if len(v) > 1 && v[1] != "" {
+				layout = v[1]
+			}
+			conv[h] = func(s string) (interface{}, error) {
+				return time.Parse(layout, s)
+			}
+		case "e164":
+			conv[h] = parseE164
+		case "enum":
+			if len(v) < 2 || v[1] == "" {
+				return nil, fmt.Errorf("enum type for column %q has no values", h)
+			}
+			vals := make(map[string]bool)
+			for _, e := range strings.Split(v[1], "|") {
+				vals[e] = true
+			}
+			conv[h] = func(s string) (interface{}, error) {
+				if !vals[s] {
+					return nil, fmt.Errorf("not an enumerated value")
+				}
+				return s, nil
+			}
+		default:
+			return nil, fmt.Errorf("unknown type %q for column %q", t, h)
+		}
+	}
+	return conv, nil
+}
+
+// row method on typeConv returns typed row converted from a key-value map row
+func (conv typeConv) row(row map[string]string) (tr TypedRow) {
+	tr.Line, tr.Vals = atoi(row["~line"], 0), make(map[string]interface{}, len(row))
+	for h, s := range row {
+		switch c := conv[h]; {
+		case h == "~line", h == "~off", h == "~lines":
+		case c == nil:
+			tr.Vals[h] = s
+		default:
+			if v, e := c(s); e != nil {
+				tr.Errs = append(tr.Errs, &RowError{Line: tr.Line, Col: h, Raw: s, Reason: typeReason(e)})
+			} else {
+				tr.Vals[h] = v
+			}
+		}
+	}
+	return
+}
+
+// typeReason returns a concise reason for a column type conversion error
+func typeReason(e error) string {
+	switch te := e.(type) {
+	case *strconv.NumError:
+		return te.Err.Error()
+	case *time.ParseError:
+		return fmt.Sprintf("not a time in %q layout", te.Layout)
+	}
+	return e.Error()
+}
+
+// parseE164 converts a phone number (with optional international prefix and formatting runes)
+// into its E.164 digit string
+func parseE164(s string) (interface{}, error) {
+	n := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '(', ')', '-', '.', '[', ']', '\t':
+			return -1
+		}
+		return r
+	}, s)
+	switch {
+	case strings.HasPrefix(n, "+"):
+		n = n[1:]
+	case strings.HasPrefix(n, "011"):
+		n = n[3:]
+	case strings.HasPrefix(n, "00"):
+		n = n[2:]
+	}
+	if len(n) < 8 || len(n) > 15 || n[0] == '0' || strings.Trim(n, "0123456789") != "" {
+		return nil, fmt.Errorf("not an E.164 number")
+	}
+	return n, nil
+}
+
+// atoi is a helper string-to-int function with selectable default value on error
+func atoi(s string, d int) int {
+	i, e := strconv.Atoi(s)
+	if e != nil {
+		return d
+	}
+	return i
+}
@@ -0,0 +1,111 @@
+package csv
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	format := []string{"id", "date", "account", "service", "cost"}
+	tests := []struct {
+		name           string
+		heads, fmt     []string
+		subset         bool
+		score          float64
+		added, removed string
+	}{
+		{"identical", format, format, false, 1, "", ""},
+		{"case and blanks", []string{"ID", " Date", "ACCOUNT ", "service", "Cost"}, format, false, 1, "", ""},
+		{"column appended", append(append([]string(nil), format...), "tax"), format, false, 0.8*5/6 + 0.2, "tax", ""},
+		{"columns reordered", []string{"id", "account", "date", "service", "cost"}, format, false, 0.8 + 0.2*4/5, "", ""},
+		{"column renamed", []string{"id", "date", "acct", "service", "cost"}, format, false, 0.8*4/6 + 0.2, "acct", "account"},
+		{"column removed", []string{"id", "date", "account", "cost"}, format, false, 0.8*4/5 + 0.2, "", "service"},
+		{"disjoint", []string{"a", "b"}, format, false, 0, "a,b", "id,date,account,service,cost"},
+		{"column map subset", []string{"x", "id", "y", "cost", "service", "z"}, []string{"id", "service", "cost"}, true,
+			(0.8 + 0.2*2/3) * 0.9, "", ""},
+		{"column map partial", []string{"id", "cost", "x"}, []string{"id", "service", "cost"}, true, (0.8*2/3 + 0.2) * 0.9, "", "service"},
+	}
+	for _, tt := range tests {
+		score, added, removed := similarity(tt.heads, tt.fmt, tt.subset)
+		if math.Abs(score-tt.score) > 1e-9 {
+			t.Errorf("%s: score %.4f, want %.4f", tt.name, score, tt.score)
+		}
+		if strings.Join(added, ",") != tt.added || strings.Join(removed, ",") != tt.removed {
+			t.Errorf("%s: added %v removed %v, want %q %q", tt.name, added, removed, tt.added, tt.removed)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	set := &Settings{}
+	set.Cache(strings.NewReader(`{
+		"cur": {"Format": "CUR", "Cols": "id,date,account,service,cost", "Head": ["id","date","account","service","cost"]},
+		"cdr": {"Format": "CDR", "Cols": "!type:={V},from,to,secs"},
+		"misc": {"Format": "misc", "Head": ["name","value"]}
+	}`))
+	tests := []struct {
+		name    string
+		heads   []string
+		sig     string
+		applied bool // at MatchMin 0.8
+	}{
+		{"column appended", []string{"id", "date", "account", "service", "cost", "tax"}, "cur", true},
+		{"columns reordered", []string{"date", "id", "account", "service", "cost"}, "cur", true},
+		{"columns renamed", []string{"id", "day", "acct", "service", "cost"}, "cur", false},
+		{"column map heads", []string{"seq", "type", "from", "to", "secs", "rate"}, "cdr", true},
+		{"column map heads partial", []string{"from", "to", "duration"}, "cdr", false},
+		{"unknown", []string{"alpha", "beta"}, "", false},
+	}
+	for _, tt := range tests {
+		fm := set.Nearest(tt.heads)
+		switch {
+		case tt.sig == "" && fm != nil:
+			t.Errorf("%s: nearest %q (%.3f), want none", tt.name, fm.Sig, fm.Score)
+		case tt.sig == "":
+		case fm == nil || fm.Sig != tt.sig:
+			t.Errorf("%s: nearest %+v, want %q", tt.name, fm, tt.sig)
+		case (fm.Score >= 0.8) != tt.applied:
+			t.Errorf("%s: score %.3f, applied at 0.8 %v", tt.name, fm.Score, tt.applied)
+		}
+	}
+}
+
+func TestNearestApplied(t *testing.T) {
+	dir := t.TempDir()
+	sfn := filepath.Join(dir, "settings.json")
+	os.WriteFile(sfn, []byte(`{"cur": {"Format": "CUR", "Cols": "id,cost", "Head": ["id","date","account","service","cost"]}}`), 0644)
+	tests := []struct {
+		name, heading string
+		matchMin      float64
+		applied       bool
+	}{
+		{"appended", "id,date,account,service,cost,tax", 0.8, true},
+		{"appended without MatchMin", "id,date,account,service,cost,tax", 0, false},
+		{"renamed below MatchMin", "id,day,acct,service,cost", 0.8, false},
+		{"MatchMin above 1", "id,date,account,service,cost,tax", 1.5, false},
+	}
+	for _, tt := range tests {
+		fn := filepath.Join(dir, "r.csv")
+		n := strings.Count(tt.heading, ",")
+		os.WriteFile(fn, []byte(tt.heading+"\n1"+strings.Repeat(",2", n)+"\n3"+strings.Repeat(",4", n)+"\n"), 0644)
+		set := &Settings{Location: sfn}
+		set.Cache(nil)
+		res := &Resource{Location: fn, SettingsCache: set, MatchMin: tt.matchMin}
+		if e := res.Open(nil); e != nil {
+			t.Fatalf("%s: %v", tt.name, e)
+		}
+		res.Close()
+		switch {
+		case res.Nearest == nil || res.Nearest.Sig != "cur":
+			t.Errorf("%s: nearest %+v", tt.name, res.Nearest)
+		case res.Nearest.Applied != tt.applied || (res.Settings.Format == "CUR") != tt.applied:
+			t.Errorf("%s: applied %v (format %q, score %.3f), want %v", tt.name, res.Nearest.Applied, res.Settings.Format,
+				res.Nearest.Score, tt.applied)
+		case tt.applied && strings.Join(res.Heads, ",") != "id,cost":
+			t.Errorf("%s: heads %v from applied column map", tt.name, res.Heads)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	iio "github.com/sententico/cost/internal/io"
+)
+
+type (
+	// parChunk record-aligned span of CSV content parsed by a parallel worker
+	parChunk struct {
+		seq  int    // chunk sequence number
+		b    []byte // chunk content
+		line int    // source line number preceding chunk
+		off  int64  // content byte offset of chunk
+	}
+
+	// parResult rows and malformed rows (in source order) parsed from a parChunk
+	parResult struct {
+		seq  int
+		rows []map[string]string
+		errs []*RowError
+		at   []int // row index preceding each RowError
+		err  error // chunk scanning error
+	}
+)
+
+// parallel CSV parsing constants
+const (
+	parChunkLen = 4 << 20 // nominal chunk length
+	parAhead    = 2       // chunks in flight per worker
+)
+
+// parallel method on Resource returns true if Get may parse the resource on parallel Workers: an
+// uncompressed UTF-8 CSV resource in a regular file opened by Open
+func (res *Resource) parallel() bool {
+	return res.Workers > 1 && res.Typ == RTcsv && res.file != nil && res.finfo != nil && res.finfo.Mode().IsRegular() &&
+		res.Codec == "" && res.Encoding == "utf-8"
+}
+
+// getCSVPar method on Resource reads CSV rows like getCSV, but once the heading (or first data
+// row) is read, splits remaining content (following any Resume checkpoint) into record-aligned
+// chunks parsed on Workers; rows are written to "out" channel in source order unless Unordered
+// (ignored with Checkpoints, as a checkpoint implies all preceding rows were handled)
+func (res *Resource) getCSVPar() {
+	var vcols map[string]cmapItem
+	wid, line, off, found := 0, 0, int64(0), false
+	for rec := range res.in {
+		switch ln := rec.Text; {
+		case len(strings.TrimSpace(ln)) == 0:
+			continue
+		case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang):
+			select {
+			case res.out <- res.mark(map[string]string{"~meta": ln[len(res.Shebang):]}, rec.Line, rec.Text, rec.End):
+			case <-res.sig:
+				return
+			}
+			continue
+		case res.Comment != "" && strings.HasPrefix(ln, res.Comment):
+			continue
+		}
+		var data bool
+		if vcols, wid, data = res.csvCols(rec.Text); data {
+			line, off, found = rec.Line-1, rec.Off, true
+		} else {
+			line, off, found = rec.Line+strings.Count(rec.Text, "\n"), rec.End, true
+		}
+		break
+	}
+	if ck := res.Resume; ck != nil && ck.Off > off {
+		line, off = ck.Line, ck.Off // heading re-applied by resume; continue after checkpoint
+	}
+	close(res.isig) // halt serial reading (ReadAt below is independent of the file offset)
+	if res.isig = make(chan int); !found {
+		return
+	}
+
+	bom, b := int64(0), make([]byte, 3)
+	if n, _ := res.file.ReadAt(b, 0); n == 3 && string(b) == "\xef\xbb\xbf" {
+		bom = 3
+	}
+	seps := sepSet + strings.TrimLeft(string(res.Sep), "\x00")
+	ahead := res.Workers * parAhead
+	chunks, results, done := make(chan parChunk, ahead), make(chan parResult, ahead), make(chan struct{})
+	tokens := make(chan struct{}, ahead) // bounds chunks split but not yet delivered
+	defer close(done)
+	var serr error
+
+	go func() { // split content into record-aligned chunks
+		defer close(chunks)
+		pos, line, size := off, line, res.finfo.Size()-bom
+		for seq, blen := 0, parChunkLen; pos < size; seq++ {
+			b := make([]byte, blen)
+			n, e := res.file.ReadAt(b, bom+pos)
+			if e != nil && e != io.EOF {
+				serr = e
+				return
+			}
+			end := n
+			if pos+int64(n) < size {
+				if end = iio.RecEnd(b[:n], seps); end == 0 {
+					seq, blen = seq-1, blen*2 // no complete record in chunk
+					continue
+				}
+			}
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+			chunks <- parChunk{seq: seq, b: b[:end], line: line, off: pos}
+			pos, line, blen = pos+int64(end), line+bytes.Count(b[:end], []byte{'\n'}), parChunkLen
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < res.Workers; w++ {
+		wg.Add(1)
+		go func() { // parse chunks
+			defer wg.Done()
+			for ch := range chunks {
+				pr := parResult{seq: ch.seq}
+				r, _, _ := iio.Decode(ioutil.NopCloser(bytes.NewReader(ch.b)), "utf-8")
+				pr.err = iio.ScanRec(r, seps, ch.line, ch.off, func(rec iio.Rec) bool {
+					switch ln := rec.Text; {
+					case len(strings.TrimSpace(ln)) == 0:
+					case res.Shebang != "" && strings.HasPrefix(ln, res.Shebang):
+						pr.rows = append(pr.rows, res.mark(map[string]string{"~meta": ln[len(res.Shebang):]}, rec.Line, rec.Text, rec.End))
+					case res.Comment != "" && strings.HasPrefix(ln, res.Comment):
+					default:
+						if m, re := res.csvRow(rec, vcols, wid); re != nil {
+							pr.errs, pr.at = append(pr.errs, re), append(pr.at, len(pr.rows))
+						} else if m != nil {
+							pr.rows = append(pr.rows, m)
+						}
+					}
+					return true
+				})
+				select {
+				case results <- pr:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending, next := make(map[int]parResult), 0
+	for pr := range results {
+		if !res.Unordered || res.Checkpoints {
+			pending[pr.seq] = pr
+			for pr, ok := pending[next]; ok; pr, ok = pending[next] {
+				if delete(pending, next); !res.putChunk(pr) {
+					return
+				}
+				next++
+				<-tokens
+			}
+		} else if !res.putChunk(pr) {
+			return
+		} else {
+			<-tokens
+		}
+	}
+	if serr != nil {
+		panic(fmt.Errorf("error reading CSV resource (%v)", serr))
+	}
+}
+
+// putChunk method on Resource sends rows and malformed rows parsed from a chunk to the Get
+// consumer, returning false if the resource is closed
+func (res *Resource) putChunk(pr parResult) bool {
+	if pr.err != nil {
+		panic(fmt.Errorf("error scanning lines (%v)", pr.err))
+	}
+	e := 0
+	for i, m := range pr.rows {
+		for ; e < len(pr.errs) && pr.at[e] == i; e++ {
+			if !res.rowErr(pr.errs[e], "CSV") {
+				return false
+			}
+		}
+		select {
+		case res.out <- m:
+		case <-res.sig:
+			return false
+		}
+	}
+	for ; e < len(pr.errs); e++ {
+		if !res.rowErr(pr.errs[e], "CSV") {
+			return false
+		}
+	}
+	return true
+}
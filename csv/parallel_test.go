@@ -0,0 +1,107 @@
+package csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var bench struct {
+	once sync.Once
+	dir  string
+	fn   string
+	size int64
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if bench.dir != "" {
+		os.RemoveAll(bench.dir)
+	}
+	os.Exit(code)
+}
+
+// benchCSV returns the location and size of a multi-MB CSV file (with some multi-line quoted
+// fields) written once for benchmarks
+func benchCSV(b testing.TB) (string, int64) {
+	bench.once.Do(func() {
+		var e error
+		if bench.dir, e = os.MkdirTemp("", "csvbench"); e != nil {
+			b.Fatal(e)
+		}
+		var sb strings.Builder
+		sb.WriteString("id,account,service,region,usage,cost,note\n")
+		for i := 0; i < 200000; i++ {
+			note := "ok"
+			if i%97 == 0 {
+				note = "\"multi\nline, quoted\""
+			}
+			fmt.Fprintf(&sb, "%d,acct-%04d,svc-%02d,us-east-%d,%d.%03d,%d.%02d,%s\n", i, i%5000, i%40, i%4, i%1000, i%997, i%10000, i%100, note)
+		}
+		bench.fn = filepath.Join(bench.dir, "bench.csv")
+		if e = os.WriteFile(bench.fn, []byte(sb.String()), 0644); e != nil {
+			b.Fatal(e)
+		}
+		bench.size = int64(sb.Len())
+	})
+	return bench.fn, bench.size
+}
+
+// BenchmarkGet compares serial Get with parallel Workers (ordered delivery)
+func BenchmarkGet(b *testing.B) {
+	fn, size := benchCSV(b)
+	for _, w := range []int{0, 2, 4, runtime.GOMAXPROCS(0)} {
+		b.Run(fmt.Sprintf("workers=%d", w), func(b *testing.B) {
+			b.SetBytes(size)
+			for i := 0; i < b.N; i++ {
+				res := &Resource{Location: fn, Workers: w}
+				if e := res.Open(nil); e != nil {
+					b.Fatal(e)
+				}
+				in, err := res.Get()
+				n := 0
+				for range in {
+					n++
+				}
+				if e := <-err; e != nil || n != 200000 {
+					b.Fatalf("read %d rows: %v", n, e)
+				}
+				res.Close()
+			}
+		})
+	}
+}
+
+func TestParallelOrder(t *testing.T) {
+	fn, _ := benchCSV(t)
+	serial := getRows(t, &Resource{Location: fn})
+	par := getRows(t, &Resource{Location: fn, Workers: 4})
+	if len(par) != len(serial) {
+		t.Fatalf("parallel read %d rows, serial %d", len(par), len(serial))
+	}
+	for i := range serial {
+		if par[i]["id"] != serial[i]["id"] || par[i]["note"] != serial[i]["note"] || par[i]["~line"] != serial[i]["~line"] {
+			t.Fatalf("row %d: parallel %v, serial %v", i, par[i], serial[i])
+		}
+	}
+}
+
+func TestParallelResume(t *testing.T) {
+	fn, _ := benchCSV(t)
+	res := &Resource{Location: fn, Checkpoints: true, Workers: 4, Unordered: true}
+	rows := getRows(t, res)
+	for i, row := range rows {
+		if row["id"] != fmt.Sprint(i) {
+			t.Fatalf("row %d: id %q out of order with Checkpoints", i, row["id"])
+		}
+	}
+	ck := res.Checkpoint(rows[99999])
+	rest := getRows(t, &Resource{Location: fn, Workers: 4, Resume: &ck})
+	if len(rest) != 100000 || rest[0]["id"] != "100000" || rest[0]["~line"] != rows[100000]["~line"] {
+		t.Fatalf("resumed %d rows from %v, want 100000 from id 100000", len(rest), rest[0])
+	}
+}
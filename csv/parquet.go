@@ -0,0 +1,1039 @@
+package csv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+type (
+	// tStruct is a Thrift (compact protocol) struct decoded into values keyed by field ID
+	tStruct map[int16]interface{}
+
+	// tList is a decoded Thrift list
+	tList []interface{}
+
+	// tReader decodes Thrift compact protocol content
+	tReader struct {
+		b []byte
+		p int
+	}
+
+	// pqFile contains Parquet file metadata required to read row groups
+	pqFile struct {
+		ra     io.ReaderAt
+		size   int64
+		rows   int64         // total rows
+		cols   []*pqColumn   // supported leaf columns in schema order
+		groups []tStruct     // row group metadata
+		zd     *zstd.Decoder // zstd page decoder (if required)
+	}
+
+	// pqColumn describes a Parquet leaf column
+	pqColumn struct {
+		name   string    // column head (dotted path of visible schema names above repetition)
+		sub    string    // dotted path suffix of visible schema names below repetition
+		rpath  string    // schema path of repeated group (for map key/value pairing)
+		leaf   int       // leaf index in row group column chunks
+		typ    int64     // physical type
+		tlen   int       // fixed-length byte array length
+		conv   int64     // converted type (-1 if none)
+		lt     tStruct   // logical type
+		scale  int       // decimal scale
+		maxDef int       // maximum definition level
+		maxRep int       // maximum repetition level
+		repDef int       // definition level of repeated group
+		mapKey bool      // map key column
+		mapVal bool      // map value column
+		key    *pqColumn // map key column (for map value columns)
+		kind   byte      // schema walk state: LIST ('L'), MAP ('M'), element ('E'), key/value ('V')
+		hidden bool      // schema walk state: element name hidden
+	}
+
+	// pqData contains decoded column values for rows of a row group (by row, or by row element list)
+	pqData struct {
+		flat  []string
+		lists [][]string
+	}
+
+	// pqCursor decodes a column chunk page by page, buffering values of rows not yet taken
+	pqCursor struct {
+		c        *pqColumn
+		codec    int64    // page compression codec
+		off, end int64    // next page offset, and end of column chunk
+		got, nv  int64    // values decoded, and in column chunk
+		dict     []string // dictionary page values
+		buf      pqData   // decoded rows not yet taken (last list row may continue on next page)
+	}
+
+	// pqGroup contains decoded values of projected columns for a batch of row group rows
+	pqGroup struct {
+		cols []*pqColumn
+		data map[*pqColumn]*pqData
+		n    int
+	}
+)
+
+// pqBatch is the number of row group rows decoded at once (bounding memory per column chunk)
+var pqBatch = 4096
+
+var (
+	pqMagic      = []byte("PAR1")
+	errParquet   = fmt.Errorf("malformed Parquet content")
+	pqCodecNames = map[int64]string{0: "", 1: "snappy", 2: "gzip", 3: "lzo", 4: "brotli", 5: "lz4", 6: "zstd", 7: "lz4_raw"}
+)
+
+// openParquet method on Resource loads Parquet file metadata if resource is a Parquet file
+// (setting resource type and codec), returning an equivalent reader
+func (res *Resource) openParquet(r io.ReadCloser) (io.ReadCloser, error) {
+	ra, size, r, e := res.sniff(r, pqMagic)
+	if ra == nil || e != nil {
+		return r, e
+	}
+	if res.pq, e = loadParquet(ra, size); e != nil {
+		return nil, e
+	}
+	res.Typ, res.Sep, res.Encoding = RTparquet, '\x00', "utf-8"
+	if len(res.pq.groups) > 0 && len(res.pq.cols) > 0 {
+		res.Codec = pqCodecNames[res.pq.chunk(res.pq.groups[0], res.pq.cols[0]).int(4)]
+	}
+	return r, nil
+}
+
+// peekParquet method on Resource previews leading Parquet rows (splitting them by key) and sets
+// format signature (MD5 hash of comma-separated column heads) and estimated rows
+func (res *Resource) peekParquet() {
+	pf, heads, seen := res.pq, []string{}, make(map[string]bool)
+	for _, c := range pf.cols {
+		if !c.mapKey && !seen[c.name] {
+			heads, seen[c.name] = append(heads, c.name), true
+		}
+	}
+	res.Rows, res.Heading = int(pf.rows), false
+	res.Sig = fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(heads, ","))))
+
+	var rows []map[string]string
+	if len(pf.groups) > 0 {
+		pf.scan(pf.groups[0], pf.cols, previewLines, func(g *pqGroup) bool {
+			for r := 0; r < g.n; r++ {
+				rows = append(rows, g.row(r))
+			}
+			return true
+		})
+	}
+	res.jkeys, seen = nil, make(map[string]bool)
+	for _, c := range pf.cols {
+		switch {
+		case c.mapKey || seen[c.name]:
+		case c.maxRep == 0:
+			res.jkeys, seen[c.name] = append(res.jkeys, c.name), true
+		default:
+			var keys []string
+			for _, row := range rows {
+				for k := range row {
+					if strings.HasPrefix(k, c.name+".") && !seen[k] {
+						keys, seen[k] = append(keys, k), true
+					}
+				}
+			}
+			sort.Strings(keys)
+			res.jkeys = append(res.jkeys, keys...)
+		}
+	}
+
+	var sb strings.Builder
+	w := Writer{Sep: ','}
+	w.Open(&sb)
+	for _, row := range rows {
+		sl := make([]string, 0, len(res.jkeys))
+		for _, k := range res.jkeys {
+			sl = append(sl, row[k])
+		}
+		sb.Reset()
+		w.PutFields(sl)
+		res.Split, res.Preview = append(res.Split, sl), append(res.Preview, strings.TrimSuffix(sb.String(), "\n"))
+	}
+	res.applySettings()
+}
+
+// getParquet method on Resource streams Parquet rows by row group (decoded in batches), reading
+// only columns projected by the column map; "~line" (and any "~off") is the row number, and row
+// groups wholly preceding any Resume checkpoint are skipped
+func (res *Resource) getParquet() {
+	cols, _ := parseCMap(res.Cols, false, 0xffff)
+	proj, line, skip := res.pq.project(cols), 0, 0
+	if res.Resume != nil {
+		skip = res.Resume.Line
+	}
+	for _, rg := range res.pq.groups {
+		if n := int(rg.int(3)); line+n <= skip {
+			line += n
+			continue
+		}
+		if !res.pq.scan(rg, proj, 0, func(g *pqGroup) bool {
+			for r := 0; r < g.n; r++ {
+				if line++; line > skip && !res.putRow(cols, g.row(r), line, int64(line)) {
+					return false
+				}
+			}
+			return true
+		}) {
+			return
+		}
+	}
+}
+
+// loadParquet returns Parquet file metadata (schema columns and row groups) read from "ra"
+func loadParquet(ra io.ReaderAt, size int64) (pf *pqFile, e error) {
+	defer func() {
+		if i := recover(); i != nil {
+			pf, e = nil, fmt.Errorf("Parquet metadata problem (%v)", i)
+		}
+	}()
+	tail := make([]byte, 8)
+	if size < 12 {
+		panic(errParquet)
+	} else if _, e = ra.ReadAt(tail, size-8); e != nil {
+		panic(e)
+	} else if string(tail[4:]) == "PARE" {
+		panic(fmt.Errorf("encrypted Parquet footer unsupported"))
+	} else if !bytes.Equal(tail[4:], pqMagic) {
+		panic(errParquet)
+	}
+	mlen := int64(binary.LittleEndian.Uint32(tail))
+	if mlen > size-12 {
+		panic(errParquet)
+	}
+	meta := make([]byte, mlen)
+	if _, e = ra.ReadAt(meta, size-8-mlen); e != nil {
+		panic(e)
+	}
+
+	fm := (&tReader{b: meta}).structure()
+	pf = &pqFile{ra: ra, size: size, rows: fm.int(3)}
+	schema := fm.list(2)
+	if len(schema) == 0 {
+		panic(errParquet)
+	}
+	root, leaf := schema[0].(tStruct), 0
+	if pf.walk(schema, 1, int(root.int(5)), pqColumn{conv: -1}, &leaf) != len(schema) {
+		panic(errParquet)
+	}
+	for _, c := range pf.cols {
+		if c.mapVal {
+			for _, k := range pf.cols {
+				if k.mapKey && k.rpath == c.rpath {
+					c.key = k
+				}
+			}
+		}
+	}
+	for _, g := range fm.list(4) {
+		if rg, ok := g.(tStruct); !ok || len(rg.list(1)) != leaf {
+			panic(errParquet)
+		} else {
+			pf.groups = append(pf.groups, rg)
+		}
+	}
+	return pf, nil
+}
+
+// walk method on pqFile adds leaf columns for "n" schema elements beginning at "els[i]" (with
+// parent state "c"), returning the index following them
+func (pf *pqFile) walk(els tList, i, n int, c pqColumn, leaf *int) int {
+	for ; n > 0; n-- {
+		if i >= len(els) {
+			panic(errParquet)
+		}
+		el, _ := els[i].(tStruct)
+		cc, name, rep := c, el.str(4), false
+		i++
+		switch el.int(3) {
+		case 1: // OPTIONAL
+			cc.maxDef++
+		case 2: // REPEATED
+			cc.maxDef, cc.maxRep, rep = cc.maxDef+1, cc.maxRep+1, true
+			cc.repDef = cc.maxDef
+		}
+		children, ok := el[5].(int64)
+		group := ok && children > 0
+
+		switch cc.kind, cc.hidden = 0, false; {
+		case c.kind == 'L': // repeated LIST group (element name hidden if sole child)
+			cc.kind, cc.hidden = 'E', group && children == 1
+		case c.kind == 'M': // repeated MAP key_value group
+			cc.kind = 'V'
+		case c.kind == 'V':
+			cc.mapKey, cc.mapVal = name == "key", name != "key"
+		case c.kind == 'E' && c.hidden:
+		case c.maxRep > 0:
+			cc.sub += "." + name
+		case cc.name == "":
+			cc.name = name
+		default:
+			cc.name += "." + name
+		}
+		if rep {
+			cc.rpath = c.rpath + "/" + name
+		}
+		switch conv, lt := el.int(6), el.st(10); {
+		case !group || c.kind == 'M':
+		case el.has(6) && conv == 3 || lt.has(3):
+			cc.kind = 'L'
+		case el.has(6) && conv == 1 || lt.has(2):
+			cc.kind = 'M'
+		}
+
+		if group {
+			i = pf.walk(els, i, int(children), cc, leaf)
+			continue
+		}
+		col := cc
+		col.leaf, *leaf = *leaf, *leaf+1
+		col.typ, col.tlen, col.lt, col.scale = el.int(1), int(el.int(2)), el.st(10), int(el.int(7))
+		if el.has(6) {
+			col.conv = el.int(6)
+		} else {
+			col.conv = -1
+		}
+		if d := col.lt.st(5); d != nil {
+			col.scale = int(d.int(1))
+		}
+		if col.maxRep <= 1 { // nested repetition unsupported
+			pf.cols = append(pf.cols, &col)
+		}
+	}
+	return i
+}
+
+// project method on pqFile returns columns projected by column map "cols" (all if empty)
+func (pf *pqFile) project(cols map[string]cmapItem) (proj []*pqColumn) {
+	if len(cols) == 0 {
+		return pf.cols
+	}
+	keys, sel := make(map[*pqColumn]bool), func(c *pqColumn) bool {
+		if _, ok := cols[c.name]; ok && c.maxRep == 0 {
+			return true
+		}
+		for h := range cols {
+			if c.maxRep > 0 && strings.HasPrefix(h, c.name+".") {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range pf.cols {
+		if !c.mapKey && sel(c) {
+			proj = append(proj, c)
+			if c.key != nil {
+				keys[c.key] = true
+			}
+		}
+	}
+	for k := range keys {
+		proj = append(proj, k)
+	}
+	return
+}
+
+// chunk method on pqFile returns column chunk metadata of column "c" in row group "rg"
+func (pf *pqFile) chunk(rg tStruct, c *pqColumn) tStruct {
+	cc, _ := rg.list(1)[c.leaf].(tStruct)
+	if cc.has(1) {
+		panic(fmt.Errorf("external Parquet column chunks unsupported"))
+	}
+	return cc.st(3)
+}
+
+// scan method on pqFile decodes columns "cols" of row group "rg" in batches of up to pqBatch rows
+// (through "limit" rows if not 0), calling "f" with each until it returns false (as does scan)
+func (pf *pqFile) scan(rg tStruct, cols []*pqColumn, limit int, f func(*pqGroup) bool) bool {
+	n := int(rg.int(3))
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	ks := make([]*pqCursor, len(cols))
+	for i, c := range cols {
+		ks[i] = pf.cursor(c, pf.chunk(rg, c))
+	}
+	for r := 0; r < n; {
+		g := &pqGroup{cols: cols, data: make(map[*pqColumn]*pqData, len(cols)), n: n - r}
+		if g.n > pqBatch {
+			g.n = pqBatch
+		}
+		for _, k := range ks {
+			g.data[k.c] = pf.take(k, g.n)
+		}
+		if r += g.n; !f(g) {
+			return false
+		}
+	}
+	return true
+}
+
+// row method on pqGroup returns flattened key-value map of row "r" (empty values omitted)
+func (g *pqGroup) row(r int) map[string]string {
+	m := make(map[string]string, len(g.cols))
+	for _, c := range g.cols {
+		d := g.data[c]
+		switch {
+		case c.mapKey:
+		case c.maxRep == 0:
+			if v := d.flat[r]; v != "" {
+				m[c.name] = v
+			}
+		case c.key != nil:
+			keys := g.data[c.key].lists[r]
+			for i, v := range d.lists[r] {
+				if i < len(keys) && v != "" {
+					m[c.name+"."+keys[i]+c.sub] = v
+				}
+			}
+		default:
+			for i, v := range d.lists[r] {
+				if v != "" {
+					m[c.name+"."+strconv.Itoa(i)+c.sub] = v
+				}
+			}
+		}
+	}
+	return m
+}
+
+// cursor method on pqFile returns a cursor positioned at the first page of column "c" chunk "md"
+func (pf *pqFile) cursor(c *pqColumn, md tStruct) *pqCursor {
+	k := &pqCursor{c: c, codec: md.int(4), off: md.int(9), nv: md.int(5)}
+	if o := md.int(11); o > 0 && o < k.off {
+		k.off = o
+	}
+	if k.end = k.off + md.int(7); k.off < 4 || k.end > pf.size {
+		panic(errParquet)
+	}
+	return k
+}
+
+// take method on pqFile returns values of the next "rows" rows of cursor "k", decoding pages as
+// needed; panics if the column chunk has fewer rows
+func (pf *pqFile) take(k *pqCursor, rows int) *pqData {
+	d, c := &pqData{}, k.c
+	for more := true; ; {
+		if n := len(k.buf.flat) + len(k.buf.lists); n > rows || n == rows && (c.maxRep == 0 || !more) {
+			break // list rows are complete when followed by another (or at chunk end)
+		} else if !more {
+			panic(fmt.Errorf("Parquet column %q missing %d row(s)", c.name, rows-n))
+		}
+		more = pf.page(k)
+	}
+	if c.maxRep == 0 {
+		d.flat, k.buf.flat = k.buf.flat[:rows:rows], k.buf.flat[rows:]
+	} else {
+		d.lists, k.buf.lists = k.buf.lists[:rows:rows], k.buf.lists[rows:]
+	}
+	return d
+}
+
+// page method on pqFile decodes the next page of cursor "k" into its buffer, returning false if
+// the column chunk is exhausted
+func (pf *pqFile) page(k *pqCursor) bool {
+	c := k.c
+	if k.got >= k.nv || k.off >= k.end {
+		return false
+	}
+	hb := make([]byte, k.end-k.off)
+	if len(hb) > 64<<10 {
+		hb = hb[:64<<10]
+	}
+	if _, e := pf.ra.ReadAt(hb, k.off); e != nil {
+		panic(e)
+	}
+	t := tReader{b: hb}
+	ph := t.structure()
+	cs := ph.int(3)
+	if k.off += int64(t.p); cs < 0 || k.off+cs > k.end {
+		panic(errParquet)
+	}
+	page := make([]byte, cs)
+	if _, e := pf.ra.ReadAt(page, k.off); e != nil {
+		panic(e)
+	}
+	k.off += cs
+
+	switch ph.int(1) {
+	case 2: // DICTIONARY_PAGE
+		dh := ph.st(7)
+		k.dict = c.plain(pf.inflate(k.codec, page, ph.int(2)), int(dh.int(1)))
+	case 0: // DATA_PAGE
+		dh := ph.st(5)
+		n, b := int(dh.int(1)), pf.inflate(k.codec, page, ph.int(2))
+		var reps, defs []int
+		if c.maxRep > 0 {
+			reps, b = levels(b, c.maxRep, n)
+		}
+		if c.maxDef > 0 {
+			defs, b = levels(b, c.maxDef, n)
+		}
+		k.buf.add(c, reps, defs, c.values(dh.int(2), b, present(defs, c.maxDef, n), k.dict), n)
+		k.got += int64(n)
+	case 3: // DATA_PAGE_V2
+		dh := ph.st(8)
+		n, rl, dl := int(dh.int(1)), int(dh.int(6)), int(dh.int(5))
+		if rl < 0 || dl < 0 || rl+dl > len(page) {
+			panic(errParquet)
+		}
+		var reps, defs []int
+		if c.maxRep > 0 {
+			reps = unhybrid(page[:rl], bits.Len(uint(c.maxRep)), n)
+		}
+		if c.maxDef > 0 {
+			defs = unhybrid(page[rl:rl+dl], bits.Len(uint(c.maxDef)), n)
+		}
+		b := page[rl+dl:]
+		if z, ok := dh[7].(bool); !ok || z {
+			b = pf.inflate(k.codec, b, ph.int(2)-int64(rl+dl))
+		}
+		k.buf.add(c, reps, defs, c.values(dh.int(4), b, present(defs, c.maxDef, n), k.dict), n)
+		k.got += int64(n)
+	}
+	return true
+}
+
+// add method on pqData assembles "n" page values into rows by repetition and definition levels
+func (d *pqData) add(c *pqColumn, reps, defs []int, vals []string, n int) {
+	for i, vi := 0, 0; i < n; i++ {
+		v := ""
+		if defs == nil || defs[i] == c.maxDef {
+			if vi >= len(vals) {
+				panic(errParquet)
+			}
+			v, vi = vals[vi], vi+1
+		}
+		switch {
+		case c.maxRep == 0:
+			d.flat = append(d.flat, v)
+			continue
+		case reps[i] == 0:
+			d.lists = append(d.lists, nil)
+		case len(d.lists) == 0:
+			panic(errParquet)
+		}
+		if defs == nil || defs[i] >= c.repDef {
+			d.lists[len(d.lists)-1] = append(d.lists[len(d.lists)-1], v)
+		}
+	}
+}
+
+// inflate method on pqFile returns page content "b" decompressed by "codec" (to "size" bytes)
+func (pf *pqFile) inflate(codec int64, b []byte, size int64) []byte {
+	if size < 0 || size > 1<<31 {
+		panic(errParquet)
+	}
+	switch codec {
+	case 0: // UNCOMPRESSED
+		return b
+	case 1: // SNAPPY
+		ub, e := s2.Decode(make([]byte, size), b)
+		if e != nil {
+			panic(fmt.Errorf("Parquet snappy page problem (%v)", e))
+		}
+		return ub
+	case 2: // GZIP
+		zr, e := gzip.NewReader(bytes.NewReader(b))
+		if e != nil {
+			panic(fmt.Errorf("Parquet gzip page problem (%v)", e))
+		}
+		ub := bytes.NewBuffer(make([]byte, 0, size))
+		if _, e = io.Copy(ub, zr); e != nil {
+			panic(fmt.Errorf("Parquet gzip page problem (%v)", e))
+		}
+		return ub.Bytes()
+	case 6: // ZSTD
+		if pf.zd == nil {
+			pf.zd, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		}
+		ub, e := pf.zd.DecodeAll(b, make([]byte, 0, size))
+		if e != nil {
+			panic(fmt.Errorf("Parquet zstd page problem (%v)", e))
+		}
+		return ub
+	}
+	panic(fmt.Errorf("unsupported Parquet %q codec", pqCodecNames[codec]))
+}
+
+// levels returns "n" repetition/definition levels (up to "max") of a v1 data page, with the
+// remaining page content
+func levels(b []byte, max, n int) ([]int, []byte) {
+	if len(b) < 4 {
+		panic(errParquet)
+	}
+	l := int(binary.LittleEndian.Uint32(b))
+	if l < 0 || 4+l > len(b) {
+		panic(errParquet)
+	}
+	return unhybrid(b[4:4+l], bits.Len(uint(max)), n), b[4+l:]
+}
+
+// present returns the count of non-null values among "n" values by definition level
+func present(defs []int, max, n int) int {
+	if defs == nil {
+		return n
+	}
+	c := 0
+	for _, d := range defs {
+		if d == max {
+			c++
+		}
+	}
+	return c
+}
+
+// values method on pqColumn returns "n" rendered values of column "c" encoded by "enc" in "b"
+func (c *pqColumn) values(enc int64, b []byte, n int, dict []string) []string {
+	switch enc {
+	case 0: // PLAIN
+		return c.plain(b, n)
+	case 2, 8: // PLAIN_DICTIONARY, RLE_DICTIONARY
+		if n == 0 {
+			return nil
+		} else if dict == nil || len(b) < 1 {
+			panic(errParquet)
+		}
+		vals := make([]string, 0, n)
+		for _, i := range unhybrid(b[1:], int(b[0]), n) {
+			if i >= len(dict) {
+				panic(errParquet)
+			}
+			vals = append(vals, dict[i])
+		}
+		return vals
+	case 3: // RLE (booleans)
+		if len(b) < 4 {
+			panic(errParquet)
+		}
+		vals := make([]string, 0, n)
+		for _, v := range unhybrid(b[4:], 1, n) {
+			vals = append(vals, strconv.FormatBool(v != 0))
+		}
+		return vals
+	case 5: // DELTA_BINARY_PACKED
+		ints, _ := undelta(b, n)
+		vals := make([]string, 0, n)
+		for _, v := range ints {
+			vals = append(vals, c.fmtInt(v))
+		}
+		return vals
+	case 6: // DELTA_LENGTH_BYTE_ARRAY
+		vals := make([]string, 0, n)
+		for _, v := range unlength(b, n) {
+			vals = append(vals, c.fmtBytes(v))
+		}
+		return vals
+	case 7: // DELTA_BYTE_ARRAY
+		pfx, p := undelta(b, n)
+		var prev []byte
+		vals := make([]string, 0, n)
+		for i, v := range unlength(b[p:], n) {
+			if pfx[i] < 0 || int(pfx[i]) > len(prev) {
+				panic(errParquet)
+			}
+			prev = append(append(make([]byte, 0, int(pfx[i])+len(v)), prev[:pfx[i]]...), v...)
+			vals = append(vals, c.fmtBytes(prev))
+		}
+		return vals
+	case 9: // BYTE_STREAM_SPLIT
+		w := map[int64]int{1: 4, 2: 8, 4: 4, 5: 8, 7: c.tlen}[c.typ]
+		if w == 0 || len(b) < w*n {
+			panic(errParquet)
+		}
+		pb := make([]byte, w*n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < w; j++ {
+				pb[i*w+j] = b[j*n+i]
+			}
+		}
+		return c.plain(pb, n)
+	}
+	panic(fmt.Errorf("unsupported Parquet encoding (%d) in column %q", enc, c.name))
+}
+
+// plain method on pqColumn returns "n" rendered PLAIN-encoded values of column "c" in "b"
+func (c *pqColumn) plain(b []byte, n int) []string {
+	w := map[int64]int{1: 4, 2: 8, 3: 12, 4: 4, 5: 8, 7: c.tlen}[c.typ]
+	switch {
+	case c.typ == 0 && len(b)*8 < n, w > 0 && len(b) < w*n, c.typ == 7 && w <= 0:
+		panic(errParquet)
+	}
+	vals := make([]string, 0, n)
+	for i, p := 0, 0; i < n; i, p = i+1, p+w {
+		switch c.typ {
+		case 0: // BOOLEAN
+			vals = append(vals, strconv.FormatBool(b[i/8]>>(i%8)&1 != 0))
+		case 1: // INT32
+			vals = append(vals, c.fmtInt(int64(int32(binary.LittleEndian.Uint32(b[p:])))))
+		case 2: // INT64
+			vals = append(vals, c.fmtInt(int64(binary.LittleEndian.Uint64(b[p:]))))
+		case 3: // INT96 (legacy timestamp: nanoseconds of day, Julian day)
+			ns, jd := int64(binary.LittleEndian.Uint64(b[p:])), int64(binary.LittleEndian.Uint32(b[p+8:]))
+			vals = append(vals, time.Unix((jd-2440588)*86400, ns).UTC().Format(time.RFC3339Nano))
+		case 4: // FLOAT
+			vals = append(vals, strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b[p:]))), 'g', -1, 32))
+		case 5: // DOUBLE
+			vals = append(vals, strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b[p:])), 'g', -1, 64))
+		case 6: // BYTE_ARRAY
+			if len(b) < p+4 {
+				panic(errParquet)
+			}
+			l := int(binary.LittleEndian.Uint32(b[p:]))
+			if p += 4; l < 0 || len(b) < p+l {
+				panic(errParquet)
+			}
+			vals, w = append(vals, c.fmtBytes(b[p:p+l])), l
+		case 7: // FIXED_LEN_BYTE_ARRAY
+			vals = append(vals, c.fmtBytes(b[p:p+w]))
+		default:
+			panic(errParquet)
+		}
+	}
+	return vals
+}
+
+// fmtInt method on pqColumn renders integer "v" as annotated by column logical/converted type
+func (c *pqColumn) fmtInt(v int64) string {
+	switch ts, tm := c.lt.st(8), c.lt.st(7); {
+	case ts != nil || c.conv == 9 || c.conv == 10:
+		var t time.Time
+		switch u := ts.st(2); {
+		case u.has(1) || c.conv == 9:
+			t = time.UnixMilli(v)
+		case u.has(2) || c.conv == 10:
+			t = time.UnixMicro(v)
+		default:
+			t = time.Unix(0, v)
+		}
+		if utc, ok := ts[1].(bool); ok && !utc {
+			return t.UTC().Format("2006-01-02T15:04:05.999999999")
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	case c.lt.has(6) || c.conv == 6:
+		return time.Unix(v*86400, 0).UTC().Format("2006-01-02")
+	case tm != nil || c.conv == 7 || c.conv == 8:
+		switch u := tm.st(2); {
+		case u.has(1) || c.conv == 7:
+			v *= int64(time.Millisecond)
+		case u.has(2) || c.conv == 8:
+			v *= int64(time.Microsecond)
+		}
+		return time.Unix(0, v).UTC().Format("15:04:05.999999999")
+	case c.lt.has(5) || c.conv == 5:
+		return fmtDec(big.NewInt(v), c.scale)
+	case c.conv >= 11 && c.conv <= 14 || c.lt.has(10) && c.lt.st(10)[2] == false:
+		if c.typ == 1 {
+			return strconv.FormatUint(uint64(uint32(v)), 10)
+		}
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// fmtBytes method on pqColumn renders byte array "b" as annotated by column logical/converted type
+func (c *pqColumn) fmtBytes(b []byte) string {
+	switch {
+	case c.lt.has(5) || c.conv == 5:
+		i := new(big.Int).SetBytes(b)
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+		}
+		return fmtDec(i, c.scale)
+	case c.lt.has(14) && len(b) == 16:
+		h := hex.EncodeToString(b)
+		return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+	case c.typ == 6 && utf8.Valid(b):
+		return string(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// fmtDec renders unscaled decimal integer "i" with "scale" fractional digits
+func fmtDec(i *big.Int, scale int) string {
+	s, neg := i.String(), i.Sign() < 0
+	if scale <= 0 {
+		return s
+	} else if neg {
+		s = s[1:]
+	}
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	if s = s[:len(s)-scale] + "." + s[len(s)-scale:]; neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// unhybrid returns "n" values of bit width "bw" decoded from RLE/bit-packed hybrid content "b"
+func unhybrid(b []byte, bw, n int) []int {
+	v := make([]int, 0, n)
+	if bw > 32 {
+		panic(errParquet)
+	}
+	for p := 0; len(v) < n; {
+		if p >= len(b) {
+			panic(errParquet)
+		}
+		h, k := binary.Uvarint(b[p:])
+		if k <= 0 {
+			panic(errParquet)
+		}
+		if p += k; h&1 == 0 {
+			w, x := (bw+7)/8, 0
+			if p+w > len(b) {
+				panic(errParquet)
+			}
+			for i := 0; i < w; i++ {
+				x |= int(b[p+i]) << (8 * i)
+			}
+			p += w
+			for r := h >> 1; r > 0 && len(v) < n; r-- {
+				v = append(v, x)
+			}
+		} else {
+			g := int(h>>1) * 8
+			for i := 0; i < g && len(v) < n; i++ {
+				v = append(v, int(unpack(b[p:], i*bw, bw)))
+			}
+			p += g * bw / 8
+		}
+	}
+	return v
+}
+
+// unpack returns the "bw"-bit value at bit offset "off" of LSB-first bit-packed content "b"
+// (missing trailing content read as zero bits)
+func unpack(b []byte, off, bw int) (x uint64) {
+	if bw == 0 {
+		return 0
+	} else if p := off / 8; bw <= 56 && p+8 <= len(b) {
+		return binary.LittleEndian.Uint64(b[p:]) >> (off % 8) & (1<<bw - 1)
+	}
+	for i := 0; i < bw; i++ {
+		if p := (off + i) / 8; p < len(b) && b[p]>>((off+i)%8)&1 != 0 {
+			x |= 1 << i
+		}
+	}
+	return
+}
+
+// undelta returns up to "n" DELTA_BINARY_PACKED integers decoded from "b", with bytes consumed
+func undelta(b []byte, n int) ([]int64, int) {
+	p := 0
+	uv := func() uint64 {
+		if p >= len(b) {
+			panic(errParquet)
+		}
+		x, k := binary.Uvarint(b[p:])
+		if k <= 0 {
+			panic(errParquet)
+		}
+		p += k
+		return x
+	}
+	zz := func() int64 {
+		x := uv()
+		return int64(x>>1) ^ -int64(x&1)
+	}
+	bsize, mbs, total := int(uv()), int(uv()), int(uv())
+	if mbs <= 0 || bsize <= 0 || bsize%mbs != 0 || total < 0 || total > len(b)*64+1 {
+		panic(errParquet)
+	}
+	v, last, per := make([]int64, 0, total), zz(), bsize/mbs
+	if total > 0 {
+		v = append(v, last)
+	}
+	for len(v) < total {
+		min := zz()
+		if p+mbs > len(b) {
+			panic(errParquet)
+		}
+		widths := b[p : p+mbs]
+		p += mbs
+		for _, w := range widths {
+			if len(v) >= total {
+				break
+			} else if w > 64 {
+				panic(errParquet)
+			}
+			for i := 0; i < per && len(v) < total; i++ {
+				last += min + int64(unpack(b[p:], i*int(w), int(w)))
+				v = append(v, last)
+			}
+			if p += per * int(w) / 8; p > len(b) {
+				p = len(b)
+			}
+		}
+	}
+	if len(v) > n {
+		v = v[:n]
+	}
+	return v, p
+}
+
+// unlength returns "n" DELTA_LENGTH_BYTE_ARRAY byte arrays decoded from "b"
+func unlength(b []byte, n int) [][]byte {
+	lens, p := undelta(b, n)
+	v := make([][]byte, 0, n)
+	for _, l := range lens {
+		if l < 0 || p+int(l) > len(b) {
+			panic(errParquet)
+		}
+		v, p = append(v, b[p:p+int(l)]), p+int(l)
+	}
+	if len(v) < n {
+		panic(errParquet)
+	}
+	return v
+}
+
+// next method on tReader returns the next content byte
+func (t *tReader) next() byte {
+	if t.p >= len(t.b) {
+		panic(errParquet)
+	}
+	t.p++
+	return t.b[t.p-1]
+}
+
+// uvarint method on tReader returns the next unsigned varint
+func (t *tReader) uvarint() (v uint64) {
+	for s := uint(0); s < 64; s += 7 {
+		c := t.next()
+		if v |= uint64(c&0x7f) << s; c < 0x80 {
+			return
+		}
+	}
+	panic(errParquet)
+}
+
+// varint method on tReader returns the next zigzag-encoded signed varint
+func (t *tReader) varint() int64 {
+	u := t.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// value method on tReader returns the next value of compact protocol type "typ"
+func (t *tReader) value(typ byte) interface{} {
+	switch typ {
+	case 1, 2: // BOOLEAN_TRUE, BOOLEAN_FALSE (field types)
+		return typ == 1
+	case 3: // BYTE
+		return int64(int8(t.next()))
+	case 4, 5, 6: // I16, I32, I64
+		return t.varint()
+	case 7: // DOUBLE
+		if t.p+8 > len(t.b) {
+			panic(errParquet)
+		}
+		t.p += 8
+		return math.Float64frombits(binary.LittleEndian.Uint64(t.b[t.p-8:]))
+	case 8: // BINARY
+		n := t.uvarint()
+		if n > uint64(len(t.b)-t.p) {
+			panic(errParquet)
+		}
+		t.p += int(n)
+		return t.b[t.p-int(n) : t.p]
+	case 9, 10: // LIST, SET
+		h := t.next()
+		n, et := uint64(h>>4), h&0xf
+		if n == 15 {
+			n = t.uvarint()
+		}
+		if n > uint64(len(t.b)-t.p) {
+			panic(errParquet)
+		}
+		l := make(tList, 0, n)
+		for ; n > 0; n-- {
+			if et == 1 || et == 2 {
+				l = append(l, t.next() == 1)
+			} else {
+				l = append(l, t.value(et))
+			}
+		}
+		return l
+	case 11: // MAP (skipped)
+		n := t.uvarint()
+		if n > uint64(len(t.b)-t.p) {
+			panic(errParquet)
+		}
+		if n > 0 {
+			kv := t.next()
+			for ; n > 0; n-- {
+				t.value(kv >> 4)
+				t.value(kv & 0xf)
+			}
+		}
+		return nil
+	case 12: // STRUCT
+		return t.structure()
+	}
+	panic(errParquet)
+}
+
+// structure method on tReader returns the next struct
+func (t *tReader) structure() tStruct {
+	s, id := make(tStruct), int16(0)
+	for {
+		h := t.next()
+		if h == 0 {
+			return s
+		} else if d := int16(h >> 4); d != 0 {
+			id += d
+		} else {
+			id = int16(t.varint())
+		}
+		s[id] = t.value(h & 0xf)
+	}
+}
+
+// has method on tStruct returns true if field "id" is present
+func (s tStruct) has(id int16) bool {
+	_, ok := s[id]
+	return ok
+}
+
+// int method on tStruct returns integer field "id" (0 if absent)
+func (s tStruct) int(id int16) int64 {
+	v, _ := s[id].(int64)
+	return v
+}
+
+// str method on tStruct returns binary field "id" as a string
+func (s tStruct) str(id int16) string {
+	v, _ := s[id].([]byte)
+	return string(v)
+}
+
+// st method on tStruct returns struct field "id" (nil if absent)
+func (s tStruct) st(id int16) tStruct {
+	v, _ := s[id].(tStruct)
+	return v
+}
+
+// list method on tStruct returns list field "id" (nil if absent)
+func (s tStruct) list(id int16) tList {
+	v, _ := s[id].(tList)
+	return v
+}
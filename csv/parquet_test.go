@@ -0,0 +1,152 @@
+package csv
+
+import (
+	ecsv "encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// expectRows returns rows of CSV file "fn" (omitting blank values, as Get does) with its heading
+func expectRows(t *testing.T, fn string) ([]map[string]string, []string) {
+	t.Helper()
+	f, e := os.Open(fn)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer f.Close()
+	recs, e := ecsv.NewReader(f).ReadAll()
+	if e != nil || len(recs) == 0 {
+		t.Fatalf("reading %q: %v", fn, e)
+	}
+	var rows []map[string]string
+	for _, rec := range recs[1:] {
+		row := make(map[string]string)
+		for i, v := range rec {
+			if v != "" {
+				row[recs[0][i]] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, recs[0]
+}
+
+// compareRows reports differences between Get "rows" and "want" rows (ignoring "~" columns)
+func compareRows(t *testing.T, rows, want []map[string]string) {
+	t.Helper()
+	if len(rows) != len(want) {
+		t.Fatalf("read %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		for h, v := range want[i] {
+			if row[h] != v {
+				t.Errorf("row %d column %q: got %q, want %q", i+1, h, row[h], v)
+			}
+		}
+		for h, v := range row {
+			if _, ok := want[i][h]; !ok && !strings.HasPrefix(h, "~") {
+				t.Errorf("row %d column %q: unexpected value %q", i+1, h, v)
+			}
+		}
+	}
+}
+
+func TestParquet(t *testing.T) {
+	tests := []struct {
+		file, expect, codec string
+		opt                 bool // fixture written by arrow.py (where pyarrow is installed)
+	}{
+		{"types.parquet", "types.csv", "", false},
+		{"types_snappy.parquet", "types.csv", "snappy", false},
+		{"types_gzip.parquet", "types.csv", "gzip", false},
+		{"nested.parquet", "nested.csv", "", false},
+		{"arrow_v1.parquet", "arrow.csv", "snappy", true},
+		{"arrow_v2.parquet", "arrow.csv", "snappy", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if _, e := os.Stat(filepath.Join("testdata", "parquet", tt.file)); tt.opt && os.IsNotExist(e) {
+				t.Skip("fixture not generated (run testdata/parquet/arrow.py)")
+			}
+			want, heads := expectRows(t, filepath.Join("testdata", "parquet", tt.expect))
+			res := &Resource{Location: filepath.Join("testdata", "parquet", tt.file)}
+			rows := getRows(t, res)
+			if res.Typ != RTparquet || res.Codec != tt.codec {
+				t.Errorf("type %v codec %q, want Parquet codec %q", res.Typ, res.Codec, tt.codec)
+			}
+			if res.Rows != len(want) {
+				t.Errorf("estimated %d rows, want %d", res.Rows, len(want))
+			}
+			for _, h := range heads {
+				if !hasHead(res.Heads, h) {
+					t.Errorf("column %q missing from heads %v", h, res.Heads)
+				}
+			}
+			compareRows(t, rows, want)
+		})
+	}
+}
+
+// TestParquetBatches reads fixtures in small row batches, so list rows span batches and pages
+func TestParquetBatches(t *testing.T) {
+	defer func(n int) { pqBatch = n }(pqBatch)
+	for _, pqBatch = range []int{1, 3} {
+		t.Run(fmt.Sprintf("batch=%d", pqBatch), TestParquet)
+	}
+}
+
+func TestParquetProjection(t *testing.T) {
+	want, _ := expectRows(t, filepath.Join("testdata", "parquet", "types.csv"))
+	res := &Resource{Location: filepath.Join("testdata", "parquet", "types.parquet"), Cols: "name:!{alpha},amount,ts96"}
+	var proj []map[string]string
+	for _, row := range want {
+		if row["name"] == "alpha" {
+			continue
+		}
+		m := make(map[string]string)
+		for _, h := range []string{"name", "amount", "ts96"} {
+			if v, ok := row[h]; ok {
+				m[h] = v
+			}
+		}
+		proj = append(proj, m)
+	}
+	compareRows(t, getRows(t, res), proj)
+}
+
+func TestParquetTruncated(t *testing.T) {
+	b, e := os.ReadFile(filepath.Join("testdata", "parquet", "types.parquet"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, n := range []int{len(b) - 1, len(b) / 2, 100} {
+		fn := filepath.Join(t.TempDir(), "trunc.parquet")
+		if e = os.WriteFile(fn, append(b[:n:n], "PAR1"...), 0644); e != nil {
+			t.Fatal(e)
+		}
+		res := &Resource{Location: fn}
+		if e = res.Open(nil); e == nil {
+			in, err := res.Get()
+			for range in {
+			}
+			e = <-err
+			res.Close()
+		}
+		if e == nil {
+			t.Errorf("truncated to %d bytes: no error", n)
+		}
+	}
+}
+
+// hasHead returns true if "h" is in "heads"
+func hasHead(heads []string, h string) bool {
+	for _, hh := range heads {
+		if hh == h {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package csv
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockFile acquires an exclusive advisory (flock) lock on lock file "name" (created if required),
+// waiting up to lockWait, and returns its release function
+func lockFile(name string) (func(), error) {
+	f, e := os.OpenFile(name, os.O_CREATE|os.O_RDWR, 0644)
+	if e != nil {
+		return nil, e
+	}
+	for start := time.Now(); ; time.Sleep(lockPoll) {
+		switch e = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); {
+		case e == nil:
+			return func() {
+				syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+				f.Close()
+			}, nil
+		case e != syscall.EWOULDBLOCK && e != syscall.EINTR:
+			f.Close()
+			return nil, e
+		case time.Since(start) > lockWait:
+			f.Close()
+			return nil, fmt.Errorf("lock held beyond %v", lockWait)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package csv
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockFile acquires an exclusive lock by creating lock file "name" (removing it if stale beyond
+// lockStale), waiting up to lockWait, and returns its release function
+func lockFile(name string) (func(), error) {
+	for start := time.Now(); ; time.Sleep(lockPoll) {
+		f, e := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		switch {
+		case e == nil:
+			f.Close()
+			return func() { os.Remove(name) }, nil
+		case !os.IsExist(e):
+			return nil, e
+		case time.Since(start) > lockWait:
+			return nil, fmt.Errorf("lock held beyond %v", lockWait)
+		}
+		if fi, e := os.Stat(name); e == nil && time.Since(fi.ModTime()) > lockStale {
+			os.Remove(name)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package csv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loadSettings returns a Settings cache read from "fn"
+func loadSettings(fn string) *Settings {
+	set := &Settings{Location: fn}
+	set.Cache(nil)
+	return set
+}
+
+// diskSettings returns settings items written to "fn"
+func diskSettings(t *testing.T, fn string) map[string]SettingsItem {
+	t.Helper()
+	b, e := os.ReadFile(fn)
+	if e != nil {
+		t.Fatal(e)
+	}
+	m := make(map[string]SettingsItem)
+	if e = json.Unmarshal(b, &m); e != nil {
+		t.Fatal(e)
+	}
+	return m
+}
+
+// seedSettings writes initial settings "items" to a new settings file, returning its location
+func seedSettings(t *testing.T, items map[string]SettingsItem) string {
+	t.Helper()
+	fn := filepath.Join(t.TempDir(), "settings.json")
+	set := loadSettings(fn)
+	for sig, item := range items {
+		item := item
+		set.Set(sig, &item)
+	}
+	if e := set.Sync(); e != nil {
+		t.Fatal(e)
+	}
+	return fn
+}
+
+func TestSettingsSync(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := map[string]SettingsItem{
+		"=A": {Format: "A", Cols: "a,b", Date: t0},
+		"=B": {Format: "B", Cols: "x,y", Date: t0},
+	}
+	type edit struct {
+		sig  string
+		item SettingsItem
+	}
+	tests := []struct {
+		name      string
+		first     edit // synced first
+		second    edit // synced second (both edited from base)
+		want      map[string]string
+		conflicts []string
+	}{
+		{"different formats",
+			edit{"=A", SettingsItem{Format: "A", Cols: "a,b,c", Date: t0.Add(time.Hour)}},
+			edit{"=B", SettingsItem{Format: "B", Cols: "x,y,z", Date: t0.Add(time.Minute)}},
+			map[string]string{"=A": "a,b,c", "=B": "x,y,z"}, nil},
+		{"new formats",
+			edit{"=C", SettingsItem{Format: "C", Cols: "c", Date: t0}},
+			edit{"=D", SettingsItem{Format: "D", Cols: "d", Date: t0}},
+			map[string]string{"=A": "a,b", "=B": "x,y", "=C": "c", "=D": "d"}, nil},
+		{"same edit",
+			edit{"=A", SettingsItem{Format: "A", Cols: "a", Date: t0.Add(time.Hour)}},
+			edit{"=A", SettingsItem{Format: "A", Cols: "a", Date: t0.Add(time.Hour)}},
+			map[string]string{"=A": "a"}, nil},
+		{"later date wins (synced first)",
+			edit{"=A", SettingsItem{Format: "A", Cols: "later", Date: t0.Add(2 * time.Hour)}},
+			edit{"=A", SettingsItem{Format: "A", Cols: "earlier", Date: t0.Add(time.Hour)}},
+			map[string]string{"=A": "later"}, nil},
+		{"later date wins (synced second)",
+			edit{"=A", SettingsItem{Format: "A", Cols: "earlier", Date: t0.Add(time.Hour)}},
+			edit{"=A", SettingsItem{Format: "A", Cols: "later", Date: t0.Add(2 * time.Hour)}},
+			map[string]string{"=A": "later"}, nil},
+		{"lock wins over later date",
+			edit{"=A", SettingsItem{Format: "A", Cols: "locked", Date: t0.Add(time.Hour), Lock: true}},
+			edit{"=A", SettingsItem{Format: "A", Cols: "auto", Date: t0.Add(2 * time.Hour)}},
+			map[string]string{"=A": "locked"}, nil},
+		{"lock wins (synced second)",
+			edit{"=A", SettingsItem{Format: "A", Cols: "auto", Date: t0.Add(2 * time.Hour)}},
+			edit{"=A", SettingsItem{Format: "A", Cols: "locked", Date: t0.Add(time.Hour), Lock: true}},
+			map[string]string{"=A": "locked"}, nil},
+		{"same date conflict",
+			edit{"=A", SettingsItem{Format: "A", Cols: "mine", Date: t0.Add(time.Hour)}},
+			edit{"=A", SettingsItem{Format: "A", Cols: "theirs", Date: t0.Add(time.Hour)}},
+			map[string]string{"=A": "mine", "=B": "x,y"}, []string{"=A"}},
+		{"both locked conflict",
+			edit{"=B", SettingsItem{Format: "B", Cols: "one", Date: t0, Lock: true}},
+			edit{"=B", SettingsItem{Format: "B", Cols: "two", Date: t0.Add(time.Hour), Lock: true}},
+			map[string]string{"=B": "one"}, []string{"=B"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := seedSettings(t, base)
+			s1, s2 := loadSettings(fn), loadSettings(fn)
+			s1.Set(tt.first.sig, &tt.first.item)
+			s2.Set(tt.second.sig, &tt.second.item)
+			if e := s1.Sync(); e != nil {
+				t.Fatalf("first Sync: %v", e)
+			}
+			e := s2.Sync()
+			var sc *SettingsConflict
+			switch {
+			case tt.conflicts == nil && e != nil:
+				t.Fatalf("second Sync: %v", e)
+			case tt.conflicts != nil && !errors.As(e, &sc):
+				t.Fatalf("second Sync: %v, want conflict", e)
+			case sc != nil && strings.Join(sc.Sigs, ",") != strings.Join(tt.conflicts, ","):
+				t.Errorf("conflicts %v, want %v", sc.Sigs, tt.conflicts)
+			}
+			disk := diskSettings(t, fn)
+			for sig, cols := range tt.want {
+				if disk[sig].Cols != cols {
+					t.Errorf("%s columns %q, want %q", sig, disk[sig].Cols, cols)
+				}
+			}
+			// the second cache is refreshed with the merged settings
+			if s2.Get(tt.first.sig).Cols != disk[tt.first.sig].Cols {
+				t.Errorf("%s cached %q after Sync, disk %q", tt.first.sig, s2.Get(tt.first.sig).Cols, disk[tt.first.sig].Cols)
+			}
+		})
+	}
+}
+
+func TestSettingsSyncConcurrent(t *testing.T) {
+	fn := seedSettings(t, map[string]SettingsItem{"=base": {Format: "base"}})
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			set := loadSettings(fn)
+			set.Set(fmt.Sprintf("=F%d", i), &SettingsItem{Format: fmt.Sprintf("F%d", i), Date: time.Now()})
+			errs <- set.Sync()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		if e != nil {
+			t.Errorf("Sync: %v", e)
+		}
+	}
+	disk := diskSettings(t, fn)
+	for i := 0; i < 8; i++ {
+		if disk[fmt.Sprintf("=F%d", i)].Format == "" {
+			t.Errorf("format F%d lost by concurrent Sync", i)
+		}
+	}
+	if disk["=base"].Format != "base" || len(disk) != 9 {
+		t.Errorf("%d settings after concurrent Sync", len(disk))
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "settings.json")
+	if e := replaceFile(fn, []byte("first")); e != nil {
+		t.Fatal(e)
+	}
+	if e := replaceFile(fn, []byte("second")); e != nil {
+		t.Fatal(e)
+	}
+	if b, _ := os.ReadFile(fn); string(b) != "second" {
+		t.Errorf("replaced content %q", b)
+	}
+
+	// a failed replacement (target is a non-empty directory) leaves no partial temporary file
+	blocked := filepath.Join(dir, "blocked")
+	if e := os.MkdirAll(filepath.Join(blocked, "x"), 0755); e != nil {
+		t.Fatal(e)
+	}
+	if e := replaceFile(blocked, []byte("content")); e == nil {
+		t.Errorf("replacement of directory succeeded")
+	}
+	if e := replaceFile(filepath.Join(dir, "missing", "settings.json"), []byte("content")); e == nil {
+		t.Errorf("replacement in missing directory succeeded")
+	}
+	ents, _ := os.ReadDir(dir)
+	for _, ent := range ents {
+		if n := ent.Name(); n != "settings.json" && n != "blocked" {
+			t.Errorf("file %q left by replaceFile", n)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+#!/usr/bin/env python3
+"""Writes Parquet test fixtures with pyarrow (an independent, production Parquet writer) and their
+expected CSV rendering for csv package tests; complements the hand-encoded fixtures of gen.py:
+
+  arrow_v1.parquet  required, optional (with nulls) and dictionary-encoded columns, list and map
+  arrow_v2.parquet  columns (with null and empty collections and null elements), snappy pages,
+                    several row groups and several pages per column chunk; v1 and v2 data pages
+  arrow.csv         expected rows
+
+Run from this directory (with pyarrow installed) to regenerate: python3 arrow.py
+"""
+import csv
+
+import pyarrow as pa
+import pyarrow.parquet as pq
+
+ROWS = 1000
+NAMES = ['alpha', 'beta', 'Zoë, "q"', 'delta', 'epsilon']
+KEYS = ['env', 'name', 'team']
+
+
+def rows():
+    for i in range(ROWS):
+        name = None if i % 7 == 3 else NAMES[i % len(NAMES)]
+        amount = None if i % 11 == 5 else (i % 600) * 0.25 - 20
+        if i % 9 == 4:
+            nums = None
+        elif i % 9 == 6:
+            nums = []
+        else:
+            nums = [None if (i + j) % 5 == 2 else i * 10 + j for j in range(i % 4)]
+        if i % 8 == 2:
+            tags = None
+        elif i % 8 == 5:
+            tags = []
+        else:
+            tags = [(k, None if (i + n) % 6 == 1 else '%s-%d' % (k, i % 13)) for n, k in enumerate(KEYS[:i % 3 + 1])]
+        yield i, name, amount, nums, tags
+
+
+def table():
+    rs = list(rows())
+    schema = pa.schema([
+        pa.field('id', pa.int64(), nullable=False),
+        pa.field('name', pa.string()),
+        pa.field('amount', pa.float64()),
+        pa.field('nums', pa.list_(pa.int64())),
+        pa.field('tags', pa.map_(pa.string(), pa.string())),
+    ])
+    return pa.Table.from_pylist([dict(zip(schema.names, r)) for r in rs], schema=schema)
+
+
+def fmt_float(v):
+    s = repr(v)  # shortest round-trip rendering (as Go strconv 'g' format for these magnitudes)
+    return s[:-2] if s.endswith('.0') else s
+
+
+def expected():
+    out = []
+    for i, name, amount, nums, tags in rows():
+        r = {'id': str(i)}
+        if name is not None:
+            r['name'] = name
+        if amount is not None:
+            r['amount'] = fmt_float(amount)
+        r.update({'nums.%d' % j: str(x) for j, x in enumerate(nums or []) if x is not None})
+        r.update({'tags.' + k: v for k, v in tags or [] if v is not None})
+        out.append(r)
+    return out
+
+
+if __name__ == '__main__':
+    t = table()
+    for fn, version in (('arrow_v1.parquet', '1.0'), ('arrow_v2.parquet', '2.0')):
+        pq.write_table(t, fn, compression='snappy', use_dictionary=True, data_page_version=version,
+                       data_page_size=512, row_group_size=400)
+    rs = expected()
+    heads = ['id', 'name', 'amount'] + ['nums.%d' % j for j in range(3)] + ['tags.' + k for k in KEYS]
+    with open('arrow.csv', 'w', newline='') as f:
+        wr = csv.writer(f, lineterminator='\n')
+        wr.writerow(heads)
+        for r in rs:
+            wr.writerow([r.get(h, '') for h in heads])
@@ -0,0 +1,405 @@
+#!/usr/bin/env python3
+"""Writes Parquet test fixtures and their expected CSV renderings for csv package tests.
+
+Fixtures are encoded from the Apache parquet-format specification (Thrift compact protocol
+metadata, RLE/bit-packed hybrid levels and dictionary indices, PLAIN values) using only the
+Python standard library, independently of the Go decoder:
+
+  types.parquet         flat columns over 3 row groups: dictionary-encoded strings (with
+  types_snappy.parquet  dictionary pages), nulls, INT96 timestamps, decimals (INT32, INT64 and
+  types_gzip.parquet    FIXED_LEN_BYTE_ARRAY), dates, timestamps, booleans and doubles in v1
+                        and v2 data pages (with several pages per column chunk)
+  nested.parquet        map and list columns (with null and empty collections, and a list row
+                        continued on the following v1 page)
+
+Run from this directory to regenerate: python3 gen.py
+"""
+import csv
+import datetime
+import gzip
+import struct
+from decimal import Decimal
+
+# ---- Thrift compact protocol
+
+TYPES = {'bool': 1, 'i32': 5, 'i64': 6, 'bin': 8, 'list': 9, 'struct': 12}
+
+
+def uvarint(n):
+    out = bytearray()
+    while True:
+        b, n = n & 0x7f, n >> 7
+        if not n:
+            out.append(b)
+            return bytes(out)
+        out.append(b | 0x80)
+
+
+def zigzag(n):
+    return uvarint((n << 1) ^ (n >> 63))
+
+
+def value(kind, v):
+    if kind in ('i32', 'i64'):
+        return zigzag(v)
+    if kind == 'bin':
+        b = v.encode() if isinstance(v, str) else v
+        return uvarint(len(b)) + b
+    if kind == 'struct':
+        return struct_(v)
+    if kind == 'list':
+        ekind, items = v
+        n = len(items)
+        head = bytes([n << 4 | TYPES[ekind]]) if n < 15 else bytes([0xf0 | TYPES[ekind]]) + uvarint(n)
+        return head + b''.join(value(ekind, i) for i in items)
+    raise ValueError(kind)
+
+
+def struct_(fields):
+    """encodes struct "fields" given as (id, kind, value) triples (None values omitted)"""
+    out, last = bytearray(), 0
+    for fid, kind, v in fields:
+        if v is None:
+            continue
+        t = (1 if v else 2) if kind == 'bool' else TYPES[kind]
+        out += bytes([(fid - last) << 4 | t]) if 0 < fid - last <= 15 else bytes([t]) + zigzag(fid)
+        if kind != 'bool':
+            out += value(kind, v)
+        last = fid
+    out.append(0)
+    return bytes(out)
+
+
+# ---- encodings
+
+def repeats(vals, i):
+    j = i
+    while j < len(vals) and vals[j] == vals[i]:
+        j += 1
+    return j - i
+
+
+def hybrid(vals, bw):
+    """RLE/bit-packed hybrid encoding: RLE runs of 8 or more repeats, bit-packed groups otherwise"""
+    out, i, width = bytearray(), 0, (bw + 7) // 8
+    while i < len(vals):
+        n = repeats(vals, i)
+        if n >= 8:
+            out += uvarint(n << 1) + vals[i].to_bytes(width, 'little')
+            i += n
+            continue
+        j = i + 8
+        while j < len(vals) and repeats(vals, j) < 8:
+            j += 8
+        group = vals[i:j] + [0] * (-len(vals[i:j]) % 8)
+        out += uvarint(len(group) // 8 << 1 | 1)
+        acc = nbits = 0
+        for v in group:
+            acc |= v << nbits
+            nbits += bw
+            while nbits >= 8:
+                out.append(acc & 0xff)
+                acc, nbits = acc >> 8, nbits - 8
+        i = j
+    return bytes(out)
+
+
+def plain(ptype, vals, tlen=0):
+    out = bytearray()
+    if ptype == 'BOOLEAN':
+        for i in range(0, len(vals), 8):
+            out.append(sum(1 << k for k, v in enumerate(vals[i:i + 8]) if v))
+        return bytes(out)
+    for v in vals:
+        if ptype == 'INT32':
+            out += struct.pack('<i', v)
+        elif ptype == 'INT64':
+            out += struct.pack('<q', v)
+        elif ptype == 'INT96':
+            out += v
+        elif ptype == 'DOUBLE':
+            out += struct.pack('<d', v)
+        elif ptype == 'BYTE_ARRAY':
+            b = v.encode()
+            out += struct.pack('<I', len(b)) + b
+        elif ptype == 'FIXED_LEN_BYTE_ARRAY':
+            out += v.to_bytes(tlen, 'big', signed=True)
+    return bytes(out)
+
+
+def snappy(b):
+    """snappy framing-free block of literal elements (valid, if uncompressed)"""
+    out = bytearray(uvarint(len(b)))
+    for i in range(0, len(b), 60):
+        lit = b[i:i + 60]
+        out.append(len(lit) - 1 << 2)
+        out += lit
+    return bytes(out)
+
+
+CODECS = {'UNCOMPRESSED': (0, lambda b: b), 'SNAPPY': (1, snappy), 'GZIP': (2, lambda b: gzip.compress(b, mtime=0))}
+PTYPES = {'BOOLEAN': 0, 'INT32': 1, 'INT64': 2, 'INT96': 3, 'DOUBLE': 5, 'BYTE_ARRAY': 6, 'FIXED_LEN_BYTE_ARRAY': 7}
+PLAIN, RLE, RLE_DICTIONARY = 0, 3, 8
+
+
+# ---- file layout
+
+def element(name, ptype=None, rep=None, children=None, conv=None, scale=None, prec=None, tlen=None, logical=None):
+    return [(1, 'i32', PTYPES.get(ptype)), (2, 'i32', tlen), (3, 'i32', rep), (4, 'bin', name),
+            (5, 'i32', children), (6, 'i32', conv), (7, 'i32', scale), (8, 'i32', prec),
+            (10, 'struct', logical)]
+
+
+class Writer:
+    def __init__(self, schema, codec='UNCOMPRESSED'):
+        self.out, self.schema, self.groups = bytearray(b'PAR1'), schema, []
+        self.codec, self.compress = CODECS[codec]
+
+    def page(self, header, raw, levels=b''):
+        body = self.compress(raw)
+        self.out += struct_([(1, 'i32', header[0]), (2, 'i32', len(levels) + len(raw)),
+                             (3, 'i32', len(levels) + len(body)), header[1]]) + levels + body
+
+    def chunk(self, path, ptype, pages, dictionary=None):
+        """writes a column chunk of "pages" (v1 or v2 data page tuples) following any "dictionary"
+        page, returning its ColumnChunk metadata"""
+        start, dict_off, encodings, nvals = len(self.out), None, {RLE}, 0
+        if dictionary is not None:
+            dict_off = start
+            self.page((2, (7, 'struct', [(1, 'i32', len(dictionary[0])), (2, 'i32', PLAIN)])), dictionary[1])
+        data_off = len(self.out)
+        for p in pages:
+            n, enc = p[1], p[2]
+            encodings.add(enc)
+            nvals += n
+            if p[0] == 'v1':
+                self.page((0, (5, 'struct', [(1, 'i32', n), (2, 'i32', enc), (3, 'i32', RLE), (4, 'i32', RLE)])), p[3])
+            else:
+                _, n, enc, reps, defs, nulls, vals = p
+                self.page((3, (8, 'struct', [(1, 'i32', n), (2, 'i32', nulls), (3, 'i32', n), (4, 'i32', enc),
+                                             (5, 'i32', len(defs)), (6, 'i32', len(reps))])), vals, reps + defs)
+        size = len(self.out) - start
+        return [(2, 'i64', start), (3, 'struct', [
+            (1, 'i32', PTYPES[ptype]), (2, 'list', ('i32', sorted(encodings))), (3, 'list', ('bin', path)),
+            (4, 'i32', self.codec), (5, 'i64', nvals), (6, 'i64', size), (7, 'i64', size),
+            (9, 'i64', data_off), (11, 'i64', dict_off)])]
+
+    def group(self, chunks, rows):
+        self.groups.append([(1, 'list', ('struct', chunks)), (2, 'i64', 0), (3, 'i64', rows)])
+
+    def close(self, fn):
+        meta = struct_([(1, 'i32', 1), (2, 'list', ('struct', self.schema)),
+                        (3, 'i64', sum(g[2][2] for g in self.groups)), (4, 'list', ('struct', self.groups)),
+                        (6, 'bin', 'cost csv testdata gen.py')])
+        self.out += meta + struct.pack('<I', len(meta)) + b'PAR1'
+        with open(fn, 'wb') as f:
+            f.write(self.out)
+
+
+def levels_v1(levels, maxlevel):
+    b = hybrid(levels, maxlevel.bit_length())
+    return struct.pack('<I', len(b)) + b
+
+
+# ---- types fixtures
+
+UTC = datetime.timezone.utc
+EPOCH = datetime.datetime(1970, 1, 1, tzinfo=UTC)
+
+
+def rfc3339(t):
+    s = t.strftime('%Y-%m-%dT%H:%M:%S')
+    if t.microsecond:
+        s += ('.%06d' % t.microsecond).rstrip('0')
+    return s + 'Z'
+
+
+def int96(t):
+    d = t - EPOCH
+    jd, ns = 2440588 + d.days, (d.seconds * 10**6 + d.microseconds) * 1000
+    return struct.pack('<qI', ns, jd)
+
+
+def types_rows():
+    names = ['alpha', 'beta', 'gamma', 'Zoë, "q"']
+    base = datetime.datetime(2023, 5, 1, 6, 30, tzinfo=UTC)
+    rows = []
+    for i in range(12):
+        rows.append({
+            'id': 1000 + i,
+            'name': None if i in (2, 9) else names[i * 7 % len(names)],
+            'ts96': None if i == 4 else base + datetime.timedelta(days=i, seconds=i * 61, microseconds=i * 250000),
+            'amount': None if i == 6 else (i * 12345 - 50000) * (-1 if i % 3 == 0 else 1),
+            'dec32': -5 if i == 1 else i * 1001,
+            'dec64': None if i == 11 else (i - 5) * 1234567890123,
+            'day': 19500 + i,
+            'ts': 1682921400000000 + i * 3600000000,
+            'flag': None if i == 7 else i % 2 == 0,
+            'score': None if i % 5 == 0 else i * 1.5 - 4,
+        })
+    return rows
+
+
+def types_expected(r):
+    def dec(v, scale):
+        return '' if v is None else format(Decimal(v).scaleb(-scale), 'f')
+    return {
+        'id': str(r['id']),
+        'name': r['name'] or '',
+        'ts96': '' if r['ts96'] is None else rfc3339(r['ts96']),
+        'amount': dec(r['amount'], 2),
+        'dec32': dec(r['dec32'], 3),
+        'dec64': dec(r['dec64'], 4),
+        'day': (EPOCH + datetime.timedelta(days=r['day'])).strftime('%Y-%m-%d'),
+        'ts': rfc3339(EPOCH + datetime.timedelta(microseconds=r['ts'])),
+        'flag': '' if r['flag'] is None else str(r['flag']).lower(),
+        'score': '' if r['score'] is None else repr(r['score']).removesuffix('.0'),
+    }
+
+
+TYPES_COLS = ['id', 'name', 'ts96', 'amount', 'dec32', 'dec64', 'day', 'ts', 'flag', 'score']
+
+
+def types_schema():
+    decimal = lambda s, p: [(5, 'struct', [(1, 'i32', s), (2, 'i32', p)])]
+    return [
+        element('schema', children=len(TYPES_COLS)),
+        element('id', 'INT64', 0),
+        element('name', 'BYTE_ARRAY', 1, conv=0, logical=[(1, 'struct', [])]),
+        element('ts96', 'INT96', 1),
+        element('amount', 'FIXED_LEN_BYTE_ARRAY', 1, conv=5, scale=2, prec=9, tlen=5, logical=decimal(2, 9)),
+        element('dec32', 'INT32', 0, conv=5, scale=3, prec=7, logical=decimal(3, 7)),
+        element('dec64', 'INT64', 1, conv=5, scale=4, prec=18, logical=decimal(4, 18)),
+        element('day', 'INT32', 0, conv=6, logical=[(6, 'struct', [])]),
+        element('ts', 'INT64', 0, conv=10, logical=[(8, 'struct', [(1, 'bool', True), (2, 'struct', [(2, 'struct', [])])])]),
+        element('flag', 'BOOLEAN', 1),
+        element('score', 'DOUBLE', 1),
+    ]
+
+
+def optional_v1(ptype, vals, tlen=0, split=1):
+    """v1 data pages (optional column, "split" pages) of "vals" with definition levels"""
+    pages, step = [], -(-len(vals) // split)
+    for i in range(0, len(vals), step):
+        part = vals[i:i + step]
+        present = [v for v in part if v is not None]
+        defs = [0 if v is None else 1 for v in part]
+        pages.append(('v1', len(part), PLAIN, levels_v1(defs, 1) + plain(ptype, present, tlen)))
+    return pages
+
+
+def optional_v2(ptype, vals):
+    present = [v for v in vals if v is not None]
+    defs = hybrid([0 if v is None else 1 for v in vals], 1)
+    return [('v2', len(vals), PLAIN, b'', defs, len(vals) - len(present), plain(ptype, present))]
+
+
+def write_types(fn, codec):
+    w, rows = Writer(types_schema(), codec), types_rows()
+    for g in (rows[:4], rows[4:7], rows[7:]):
+        col = lambda h: [r[h] for r in g]
+        names = col('name')
+        dictionary = sorted(set(v for v in names if v is not None))
+        idx = [dictionary.index(v) for v in names if v is not None]
+        bw = max(1, (len(dictionary) - 1).bit_length())
+        chunks = [
+            w.chunk(['id'], 'INT64', [('v1', len(g), PLAIN, plain('INT64', col('id')))]),
+            w.chunk(['name'], 'BYTE_ARRAY', [('v1', len(g), RLE_DICTIONARY, levels_v1([0 if v is None else 1 for v in names], 1) +
+                                              bytes([bw]) + hybrid(idx, bw))],
+                    dictionary=(dictionary, plain('BYTE_ARRAY', dictionary))),
+            w.chunk(['ts96'], 'INT96', optional_v1('INT96', [None if t is None else int96(t) for t in col('ts96')])),
+            w.chunk(['amount'], 'FIXED_LEN_BYTE_ARRAY', optional_v1('FIXED_LEN_BYTE_ARRAY', col('amount'), 5, split=2)),
+            w.chunk(['dec32'], 'INT32', [('v1', len(g), PLAIN, plain('INT32', col('dec32')))]),
+            w.chunk(['dec64'], 'INT64', optional_v2('INT64', col('dec64'))),
+            w.chunk(['day'], 'INT32', [('v1', len(g), PLAIN, plain('INT32', col('day')))]),
+            w.chunk(['ts'], 'INT64', [('v2', len(g), PLAIN, b'', b'', 0, plain('INT64', col('ts')))]),
+            w.chunk(['flag'], 'BOOLEAN', optional_v1('BOOLEAN', col('flag'), split=2)),
+            w.chunk(['score'], 'DOUBLE', optional_v2('DOUBLE', col('score'))),
+        ]
+        w.group(chunks, len(g))
+    w.close(fn)
+
+
+# ---- nested fixture
+
+NESTED = [
+    ('a', [('env', 'prod'), ('name', 'web')], [1, 2, 3]),
+    ('b', None, []),
+    ('c', [('env', 'dev'), ('tier', None)], None),
+    ('d', [], [7, None, 9]),
+]
+
+
+def write_nested(fn):
+    w = Writer([
+        element('schema', children=3),
+        element('id', 'BYTE_ARRAY', 0, conv=0),
+        element('tags', rep=1, children=1, conv=1),
+        element('key_value', rep=2, children=2),
+        element('key', 'BYTE_ARRAY', 0, conv=0),
+        element('value', 'BYTE_ARRAY', 1, conv=0),
+        element('nums', rep=1, children=1, conv=3),
+        element('list', rep=2, children=1),
+        element('element', 'INT32', 1),
+    ])
+    kr, kd, kv, vr, vd, vv, lr, ld, lv = ([] for _ in range(9))
+    for _, m, l in NESTED:
+        if not m:  # null (level 0) or empty (level 1) map
+            kr.append(0)
+            kd.append(0 if m is None else 1)
+            vr.append(0)
+            vd.append(0 if m is None else 1)
+        for i, (k, v) in enumerate(m or []):
+            kr.append(min(i, 1))
+            kd.append(2)
+            kv.append(k)
+            vr.append(min(i, 1))
+            vd.append(2 if v is None else 3)
+            if v is not None:
+                vv.append(v)
+        if not l:  # null or empty list
+            lr.append(0)
+            ld.append(0 if l is None else 1)
+        for i, x in enumerate(l or []):
+            lr.append(min(i, 1))
+            ld.append(2 if x is None else 3)
+            if x is not None:
+                lv.append(x)
+    lev = lambda reps, defs: levels_v1(reps, 1) + levels_v1(defs, 3)
+    sp = 2  # nums page split within first row (at level "sp", value "vs")
+    vs = sum(1 for d in ld[:sp] if d == 3)
+    w.group([
+        w.chunk(['id'], 'BYTE_ARRAY', [('v1', len(NESTED), PLAIN, plain('BYTE_ARRAY', [r[0] for r in NESTED]))]),
+        w.chunk(['tags', 'key_value', 'key'], 'BYTE_ARRAY', [('v1', len(kr), PLAIN, levels_v1(kr, 1) + levels_v1(kd, 2) + plain('BYTE_ARRAY', kv))]),
+        w.chunk(['tags', 'key_value', 'value'], 'BYTE_ARRAY', [('v1', len(vr), PLAIN, lev(vr, vd) + plain('BYTE_ARRAY', vv))]),
+        w.chunk(['nums', 'list', 'element'], 'INT32', [('v1', sp, PLAIN, lev(lr[:sp], ld[:sp]) + plain('INT32', lv[:vs])),
+                                                       ('v1', len(lr) - sp, PLAIN, lev(lr[sp:], ld[sp:]) + plain('INT32', lv[vs:]))]),
+    ], len(NESTED))
+    w.close(fn)
+
+
+def nested_expected():
+    rows = []
+    for id_, m, l in NESTED:
+        r = {'id': id_}
+        r.update({'tags.' + k: v for k, v in m or [] if v is not None})
+        r.update({'nums.%d' % i: str(x) for i, x in enumerate(l or []) if x is not None})
+        rows.append(r)
+    return rows
+
+
+def write_csv(fn, heads, rows):
+    with open(fn, 'w', newline='') as f:
+        wr = csv.writer(f, lineterminator='\n')
+        wr.writerow(heads)
+        for r in rows:
+            wr.writerow([r.get(h, '') for h in heads])
+
+
+if __name__ == '__main__':
+    for fn, codec in (('types.parquet', 'UNCOMPRESSED'), ('types_snappy.parquet', 'SNAPPY'), ('types_gzip.parquet', 'GZIP')):
+        write_types(fn, codec)
+    write_csv('types.csv', TYPES_COLS, [types_expected(r) for r in types_rows()])
+    write_nested('nested.parquet')
+    nested = nested_expected()
+    write_csv('nested.csv', sorted({h for r in nested for h in r}, key=lambda h: (h != 'id', h)), nested)
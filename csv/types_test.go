@@ -0,0 +1,113 @@
+package csv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTypes(t *testing.T) {
+	tm := func(s string) time.Time {
+		v, _ := time.Parse(time.RFC3339, s)
+		return v
+	}
+	tests := []struct {
+		typ, in string
+		want    interface{} // nil if conversion fails
+		reason  string
+	}{
+		{"int", "42", int64(42), ""},
+		{"int", "-7", int64(-7), ""},
+		{"int", "4.2", nil, "invalid syntax"},
+		{"int", "99999999999999999999", nil, "value out of range"},
+		{"dec", "3.25", 3.25, ""},
+		{"decimal", "-1e3", -1000.0, ""},
+		{"dec", "1,5", nil, "invalid syntax"},
+		{"str", " as is ", " as is ", ""},
+		{"", "x", "x", ""},
+		{"time", "2024-03-01T12:30:00Z", tm("2024-03-01T12:30:00Z"), ""},
+		{"time", "2024-03-01", nil, `not a time in "2006-01-02T15:04:05Z07:00" layout`},
+		{"time:2006-01-02", "2024-03-01", tm("2024-03-01T00:00:00Z"), ""},
+		{"time:01/02/2006 15:04", "03/01/2024 12:30", tm("2024-03-01T12:30:00Z"), ""},
+		{"time:01/02/2006 15:04", "2024-03-01 12:30", nil, `not a time in "01/02/2006 15:04" layout`},
+		{"enum:PSTN_OUTBOUND|PSTN_INBOUND", "PSTN_INBOUND", "PSTN_INBOUND", ""},
+		{"enum:PSTN_OUTBOUND|PSTN_INBOUND", "pstn_inbound", nil, "not an enumerated value"},
+		{"e164", "+44 20 7123 4567", "442071234567", ""},
+		{"e164", "011 33 1 42 68 53 00", "33142685300", ""},
+		{"e164", "0044-20-7123-4567", "442071234567", ""},
+		{"e164", "(415) 555-1234", "4155551234", ""},
+		{"e164", "+0123456789", nil, "not an E.164 number"},
+		{"e164", "12345", nil, "not an E.164 number"},
+		{"e164", "+1 415 555 12x4", nil, "not an E.164 number"},
+		{"e164", "+1234567890123456", nil, "not an E.164 number"},
+	}
+	for _, tt := range tests {
+		conv, e := parseTypes(ColTypes{"c": tt.typ})
+		if e != nil {
+			t.Fatalf("type %q: %v", tt.typ, e)
+		}
+		tr := conv.row(map[string]string{"c": tt.in, "~line": "3"})
+		switch v, ok := tr.Vals["c"]; {
+		case tt.want == nil && (ok || len(tr.Errs) != 1):
+			t.Errorf("type %q value %q: converted to %v, want error", tt.typ, tt.in, v)
+		case tt.want == nil && (tr.Errs[0].Reason != tt.reason || tr.Errs[0].Line != 3 || tr.Errs[0].Raw != tt.in):
+			t.Errorf("type %q value %q: error %+v, want reason %q", tt.typ, tt.in, tr.Errs[0], tt.reason)
+		case tt.want == nil:
+		case len(tr.Errs) > 0:
+			t.Errorf("type %q value %q: %v", tt.typ, tt.in, tr.Errs[0])
+		case v != tt.want && !(isTime(v) && v.(time.Time).Equal(tt.want.(time.Time))):
+			t.Errorf("type %q value %q: converted to %#v, want %#v", tt.typ, tt.in, v, tt.want)
+		}
+		if _, ok := tr.Vals["~line"]; ok || tr.Line != 3 {
+			t.Errorf("type %q: line %d, metadata values %v", tt.typ, tr.Line, tr.Vals)
+		}
+	}
+
+	for _, typ := range []string{"enum", "enum:", "float", "int64"} {
+		if _, e := parseTypes(ColTypes{"c": typ}); e == nil {
+			t.Errorf("type %q accepted", typ)
+		}
+	}
+}
+
+// isTime returns true if "v" is a time.Time
+func isTime(v interface{}) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func TestGetTyped(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "typed.csv")
+	content := "id,amt,dir,num\n1,2.5,OUT,+14155551234\n#comment\nx,3,IN,555\n"
+	if e := os.WriteFile(fn, []byte(content), 0644); e != nil {
+		t.Fatal(e)
+	}
+	res := &Resource{Location: fn, Comment: "#", Types: ColTypes{"id": "int", "amt": "dec", "dir": "enum:OUT|IN", "num": "e164"}}
+	if e := res.Open(nil); e != nil {
+		t.Fatal(e)
+	}
+	defer res.Close()
+	in, err := res.GetTyped()
+	var rows []TypedRow
+	for tr := range in {
+		rows = append(rows, tr)
+	}
+	if e := <-err; e != nil {
+		t.Fatal(e)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("read %d typed rows, want 2", len(rows))
+	}
+	if v := rows[0].Vals; v["id"] != int64(1) || v["amt"] != 2.5 || v["dir"] != "OUT" || v["num"] != "14155551234" || len(rows[0].Errs) > 0 {
+		t.Errorf("row 1: %v %v", v, rows[0].Errs)
+	}
+	if len(rows[1].Errs) != 2 || rows[1].Vals["amt"] != 3.0 || rows[1].Line != 4 {
+		t.Errorf("row 2 (line %d): %v, errors %v", rows[1].Line, rows[1].Vals, rows[1].Errs)
+	}
+	for _, re := range rows[1].Errs {
+		if re.Col != "id" && re.Col != "num" {
+			t.Errorf("row 2: unexpected error %v", re)
+		}
+	}
+}
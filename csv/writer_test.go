@@ -0,0 +1,98 @@
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriterGuard(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"=SUM(A1:A3)", "'=SUM(A1:A3)"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"+SUM(A1)", "'+SUM(A1)"},
+		{"-2+3+cmd|' /C calc'!A0", "'-2+3+cmd|' /C calc'!A0"},
+		{"+=1", "'+=1"},
+		{"--1", "'--1"},
+		{"-Sheet1!A1", "'-Sheet1!A1"},
+		{"-1!A1", "'-1!A1"},
+		{"-42.5", "-42.5"},
+		{"+14155551234", "+14155551234"},
+		{"+44 (20) 7123", "+44 (20) 7123"},
+		{"-", "-"},
+		{"- n/a -", "- n/a -"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		wr := Writer{Heads: []string{"v"}, Guard: true}
+		if e := wr.Open(&b); e != nil {
+			t.Fatal(e)
+		}
+		wr.PutFields([]string{tt.in})
+		wr.Close()
+		if got := unquote(strings.TrimSuffix(b.String(), "\n")); got != tt.want {
+			t.Errorf("guarded %q: wrote %q, want %q", tt.in, got, tt.want)
+		}
+
+		b.Reset()
+		wr = Writer{Heads: []string{"v"}}
+		wr.Open(&b)
+		wr.PutFields([]string{tt.in})
+		if got := unquote(strings.TrimSuffix(b.String(), "\n")); got != tt.in {
+			t.Errorf("unguarded %q: wrote %q", tt.in, got)
+		}
+	}
+}
+
+// unquote returns CSV field "f" without enclosing double-quotes (unescaping any within)
+func unquote(f string) string {
+	if len(f) >= 2 && f[0] == '"' && f[len(f)-1] == '"' {
+		return strings.ReplaceAll(f[1:len(f)-1], `""`, `"`)
+	}
+	return f
+}
+
+func TestWriterQuote(t *testing.T) {
+	tests := []struct {
+		name   string
+		wr     Writer
+		fields []string
+		want   string
+	}{
+		{"plain", Writer{}, []string{"a", "b c", ""}, "a,b c,\n"},
+		{"separator", Writer{}, []string{"a,b", "c"}, "\"a,b\",c\n"},
+		{"double-quote", Writer{}, []string{`say "hi"`, `"`}, "\"say \"\"hi\"\"\",\"\"\"\"\n"},
+		{"newlines", Writer{}, []string{"two\nlines", "cr\r"}, "\"two\nlines\",\"cr\r\"\n"},
+		{"edge blanks", Writer{}, []string{" lead", "trail "}, "\" lead\",\"trail \"\n"},
+		{"tab separator", Writer{Sep: '\t'}, []string{"a,b", "c\td"}, "a,b\t\"c\td\"\n"},
+		{"quote all", Writer{Quote: true}, []string{"a", "", `b"`}, "\"a\",\"\",\"b\"\"\"\n"},
+		{"guarded quote", Writer{Guard: true}, []string{`=HYPERLINK("x")`}, "\"'=HYPERLINK(\"\"x\"\")\"\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if e := tt.wr.Open(&b); e != nil {
+			t.Fatal(e)
+		}
+		tt.wr.PutFields(tt.fields)
+		if b.String() != tt.want {
+			t.Errorf("%s: wrote %q, want %q", tt.name, b.String(), tt.want)
+		}
+	}
+}
+
+func TestWriterColumns(t *testing.T) {
+	var b strings.Builder
+	wr := Writer{Cols: "id:2,name:1,!kind:!{x}", Heading: true}
+	if e := wr.Open(&b); e != nil {
+		t.Fatal(e)
+	}
+	wr.Put(map[string]string{"id": "1", "name": "a, b", "kind": "y"})
+	wr.Put(map[string]string{"id": "2", "name": "c", "kind": "x"}) // filtered
+	wr.Close()
+	if want := "name,id\n\"a, b\",1\n"; b.String() != want {
+		t.Errorf("wrote %q, want %q", b.String(), want)
+	}
+}
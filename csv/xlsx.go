@@ -0,0 +1,415 @@
+package csv
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// readCloser pairs a reader with the closer of its underlying resource
+	readCloser struct {
+		io.Reader
+		io.Closer
+	}
+
+	// closers closes a list of io.Closers, returning the first error
+	closers []io.Closer
+
+	// xlsxBook contains XLSX workbook parts required to render worksheet cells
+	xlsxBook struct {
+		zr     *zip.Reader
+		sheets []xlsxSheet // worksheets in workbook order
+		shared []string    // shared strings
+		dates  []bool      // date-formatted cell styles
+		d1904  bool        // 1904 date system
+	}
+
+	// xlsxSheet identifies an XLSX worksheet part
+	xlsxSheet struct {
+		name, part string
+	}
+)
+
+var (
+	xlsxMagic = []byte("PK\x03\x04")
+)
+
+// openXLSX method on Resource returns a reader of CSV-rendered worksheet rows if resource is an
+// XLSX workbook (setting resource type and separator), or an equivalent reader otherwise
+func (res *Resource) openXLSX(r io.ReadCloser) (io.ReadCloser, error) {
+	ra, size, r, e := res.sniff(r, xlsxMagic)
+	if ra == nil || e != nil {
+		return r, e
+	}
+	zr, e := zip.NewReader(ra, size)
+	if e != nil {
+		return nil, fmt.Errorf("ZIP archive problem (%v)", e)
+	}
+	book, e := loadXLSX(zr)
+	if e != nil {
+		return nil, e
+	}
+	sheet, e := book.sheet(res.Sheet)
+	if e != nil {
+		return nil, e
+	}
+	res.Typ, res.Sep, res.Sheet = RTxlsx, ',', sheet.name
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(book.render(sheet, pw))
+	}()
+	return &readCloser{pr, closers{pr, r}}, nil
+}
+
+// sniff method on Resource returns random access to resource content (with its size) if it
+// begins with "magic" (reading non-file content into memory), and an equivalent reader
+func (res *Resource) sniff(r io.ReadCloser, magic []byte) (io.ReaderAt, int64, io.ReadCloser, error) {
+	if f, ok := r.(*os.File); ok && res.finfo != nil && res.finfo.Mode().IsRegular() {
+		b := make([]byte, len(magic))
+		if _, e := f.ReadAt(b, 0); e != nil || !bytes.Equal(b, magic) {
+			return nil, 0, r, nil
+		}
+		return f, res.finfo.Size(), r, nil
+	}
+	br := bufio.NewReader(r)
+	if b, _ := br.Peek(len(magic)); !bytes.Equal(b, magic) {
+		return nil, 0, &readCloser{br, r}, nil
+	}
+	b, e := io.ReadAll(br)
+	if e != nil {
+		return nil, 0, r, e
+	}
+	return bytes.NewReader(b), int64(len(b)), r, nil
+}
+
+// Close method on closers closes all members
+func (cl closers) Close() (e error) {
+	for _, c := range cl {
+		if ce := c.Close(); e == nil {
+			e = ce
+		}
+	}
+	return
+}
+
+// loadXLSX returns workbook parts (sheet list, shared strings, date styles) of an XLSX archive
+func loadXLSX(zr *zip.Reader) (book *xlsxBook, e error) {
+	var wb struct {
+		Pr struct {
+			Date1904 bool `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if e = xlsxPart(zr, "xl/workbook.xml", &wb); e != nil {
+		return nil, fmt.Errorf("not an XLSX workbook (%v)", e)
+	} else if e = xlsxPart(zr, "xl/_rels/workbook.xml.rels", &rels); e != nil {
+		return nil, fmt.Errorf("XLSX workbook relationships problem (%v)", e)
+	}
+
+	book = &xlsxBook{zr: zr, d1904: wb.Pr.Date1904}
+	targets := make(map[string]string, len(rels.Rels))
+	for _, r := range rels.Rels {
+		if strings.HasPrefix(r.Target, "/") {
+			targets[r.ID] = r.Target[1:]
+		} else {
+			targets[r.ID] = path.Join("xl", r.Target)
+		}
+	}
+	for _, s := range wb.Sheets {
+		if t := targets[s.RID]; t != "" {
+			book.sheets = append(book.sheets, xlsxSheet{name: s.Name, part: t})
+		}
+	}
+	if len(book.sheets) == 0 {
+		return nil, fmt.Errorf("no worksheets in XLSX workbook")
+	}
+	if e = book.loadShared(); e != nil {
+		return nil, e
+	}
+	return book, book.loadStyles()
+}
+
+// xlsxPart decodes the XML part "name" of an XLSX archive into "v"
+func xlsxPart(zr *zip.Reader, name string, v interface{}) error {
+	f, e := zr.Open(name)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+	return xml.NewDecoder(f).Decode(v)
+}
+
+// sheet method on xlsxBook returns worksheet selected by name or 1-based index (first default)
+func (book *xlsxBook) sheet(sel string) (xlsxSheet, error) {
+	if sel == "" {
+		return book.sheets[0], nil
+	}
+	for _, s := range book.sheets {
+		if s.name == sel {
+			return s, nil
+		}
+	}
+	if i := atoi(sel, 0); i > 0 && i <= len(book.sheets) {
+		return book.sheets[i-1], nil
+	}
+	return xlsxSheet{}, fmt.Errorf("no %q worksheet in XLSX workbook", sel)
+}
+
+// loadShared method on xlsxBook loads shared strings (concatenating rich text runs)
+func (book *xlsxBook) loadShared() error {
+	f, e := book.zr.Open("xl/sharedStrings.xml")
+	if e != nil {
+		return nil // shared strings are optional
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	d, text, phon := xml.NewDecoder(f), false, false
+	for {
+		t, e := d.Token()
+		if e == io.EOF {
+			return nil
+		} else if e != nil {
+			return fmt.Errorf("XLSX shared strings problem (%v)", e)
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			switch tt.Name.Local {
+			case "si":
+				sb.Reset()
+			case "t":
+				text = !phon
+			case "rPh":
+				phon = true // phonetic runs excluded
+			}
+		case xml.EndElement:
+			switch tt.Name.Local {
+			case "si":
+				book.shared = append(book.shared, sb.String())
+			case "t":
+				text = false
+			case "rPh":
+				phon = false
+			}
+		case xml.CharData:
+			if text {
+				sb.Write(tt)
+			}
+		}
+	}
+}
+
+// loadStyles method on xlsxBook identifies cell styles with date number formats
+func (book *xlsxBook) loadStyles() error {
+	var st struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		Xfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if e := xlsxPart(book.zr, "xl/styles.xml", &st); e != nil {
+		return nil // styles are optional
+	}
+	custom := make(map[int]bool, len(st.NumFmts))
+	for _, nf := range st.NumFmts {
+		custom[nf.ID] = dateCode(nf.Code)
+	}
+	for _, xf := range st.Xfs {
+		id := xf.NumFmtID
+		book.dates = append(book.dates, id >= 14 && id <= 22 || id >= 45 && id <= 47 || custom[id])
+	}
+	return nil
+}
+
+// dateCode returns true if a custom number format code formats dates or times
+func dateCode(code string) bool {
+	quoted, esc, bracket, open := false, false, false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case esc:
+			esc = false
+		case r == '\\':
+			esc = true
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '[':
+			bracket, open = true, true
+		case r == ']':
+			bracket = false
+		case bracket:
+			if open && strings.ContainsRune("hms", r) {
+				return true // elapsed time ([h], [mm], [ss])
+			}
+			open = false // locale, color and condition sections skipped
+		case strings.ContainsRune("ymdhs", r):
+			return true
+		}
+	}
+	return false
+}
+
+// render method on xlsxBook writes worksheet rows to "w" as CSV (blank lines for skipped rows),
+// padding rows to the worksheet dimension width (or first row width)
+func (book *xlsxBook) render(sheet xlsxSheet, w io.Writer) error {
+	f, e := book.zr.Open(sheet.part)
+	if e != nil {
+		return fmt.Errorf("XLSX worksheet %q problem (%v)", sheet.name, e)
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(w)
+	out := Writer{Sep: ','}
+	if e = out.Open(bw); e != nil {
+		return e
+	}
+	var (
+		row         []string
+		cell, typ   string
+		style, line int
+		wid         int
+		sb          strings.Builder
+		text        bool
+	)
+	d := xml.NewDecoder(f)
+	for {
+		t, e := d.Token()
+		if e == io.EOF {
+			break
+		} else if e != nil {
+			return fmt.Errorf("XLSX worksheet %q problem (%v)", sheet.name, e)
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			switch tt.Name.Local {
+			case "dimension":
+				if ref := strings.Split(xmlAttr(tt, "ref"), ":"); len(ref) == 2 {
+					wid = cellCol(ref[1])
+				}
+			case "row":
+				r := atoi(xmlAttr(tt, "r"), line+1)
+				for line++; line < r; line++ {
+					bw.WriteByte('\n')
+				}
+				row = row[:0]
+			case "c":
+				cell, typ, style = xmlAttr(tt, "r"), xmlAttr(tt, "t"), atoi(xmlAttr(tt, "s"), 0)
+				sb.Reset()
+			case "v", "t":
+				text = true
+			}
+		case xml.EndElement:
+			switch tt.Name.Local {
+			case "v", "t":
+				text = false
+			case "c":
+				c := len(row) + 1
+				if cell != "" {
+					c = cellCol(cell)
+				}
+				for len(row) < c-1 {
+					row = append(row, "")
+				}
+				row = append(row, book.value(sb.String(), typ, style))
+			case "row":
+				if wid == 0 {
+					wid = len(row)
+				}
+				for len(row) < wid {
+					row = append(row, "")
+				}
+				if e = out.PutFields(row); e != nil {
+					return e
+				}
+			}
+		case xml.CharData:
+			if text {
+				sb.Write(tt)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// value method on xlsxBook returns rendered cell value of type "typ" and style "style"
+func (book *xlsxBook) value(v, typ string, style int) string {
+	switch typ {
+	case "s":
+		if i := atoi(v, -1); i >= 0 && i < len(book.shared) {
+			return book.shared[i]
+		}
+		return ""
+	case "b":
+		if v == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "", "n":
+		if style < len(book.dates) && book.dates[style] {
+			if f, e := strconv.ParseFloat(v, 64); e == nil {
+				return book.date(f)
+			}
+		}
+	}
+	return v
+}
+
+// date method on xlsxBook returns a date (and time, if any) string for an Excel serial date
+func (book *xlsxBook) date(f float64) string {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if book.d1904 {
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days, frac := math.Modf(f)
+	t := base.AddDate(0, 0, int(days)).Add(time.Duration(math.Round(frac*86400)) * time.Second)
+	if frac == 0 {
+		return t.Format("2006-01-02")
+	} else if days == 0 && !book.d1904 {
+		return t.Format("15:04:05")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// xmlAttr returns the value of attribute "name" of an XML element
+func xmlAttr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// cellCol returns the 1-based column number of an XLSX cell reference (like "AB12")
+func cellCol(ref string) (c int) {
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		c = c*26 + int(r-'A'+1)
+	}
+	return
+}
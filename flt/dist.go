@@ -0,0 +1,74 @@
+package flt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/internal/pfax"
+	"github.com/sententico/cost/internal/sketch"
+)
+
+// DistCol numeric column distribution: moments (Welford running mean and squared deviations) and
+// a t-digest for quantiles
+type DistCol struct {
+	N    int
+	Sum  float64
+	Min  float64
+	Max  float64
+	Mean float64
+	M2   float64 // sum of squared deviations from Mean
+	TD   *sketch.TDigest
+	Bad  int // non-numeric values skipped
+}
+
+// Dists Dist results: distributions by numeric column head (partial per file from Dist filters,
+// merged by the Dist aggregator), with column heads in order
+type Dists struct {
+	Cols []string
+	Col  map[string]*DistCol
+}
+
+// Dist returns concurrent filter accumulating value distributions of numeric columns of
+// CSV/fixed-field rows; columns are "cols" (comma-separated) unless set by "cols" parameter
+func Dist(cols string) func(chan<- *Dists, <-chan map[string]string, csv.Resource) {
+	return func(fin chan<- *Dists, in <-chan map[string]string, res csv.Resource) {
+		if p := pfax.Param("cols"); p != "" {
+			cols = p
+		}
+		comp, _ := strconv.ParseFloat(pfax.Param("comp"), 64)
+		d := &Dists{Cols: splitHeads(cols), Col: make(map[string]*DistCol)}
+		if len(d.Cols) == 0 {
+			panic(fmt.Errorf("no numeric columns specified for %q", res.Location))
+		}
+		for _, h := range d.Cols {
+			if !hasHead(res.Heads, h) {
+				panic(fmt.Errorf("column %q not found in %q", h, res.Location))
+			}
+			d.Col[h] = &DistCol{Min: math.Inf(1), Max: math.Inf(-1), TD: sketch.NewTDigest(comp)}
+		}
+
+		for row := range in {
+			if _, ok := row["~meta"]; ok {
+				continue
+			}
+			for _, h := range d.Cols {
+				c := d.Col[h]
+				if v := strings.TrimSpace(row[h]); v == "" {
+				} else if x, e := strconv.ParseFloat(v, 64); e != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+					c.Bad++
+				} else {
+					c.N++
+					dx := x - c.Mean
+					c.Mean += dx / float64(c.N)
+					c.M2 += dx * (x - c.Mean)
+					c.Sum, c.Min, c.Max = c.Sum+x, math.Min(c.Min, x), math.Max(c.Max, x)
+					c.TD.Add(x)
+				}
+			}
+		}
+		fin <- d
+	}
+}
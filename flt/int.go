@@ -1,6 +1,59 @@
-package flt
-
-import (
-	_ "github.com/sententico/cost/csv"           // stub reference
-	_ "github.com/sententico/cost/internal/pfax" // stub reference
-)
+package flt
+
+import (
+	"sync"
+
+	"github.com/sententico/cost/internal/pfax"
+	"github.com/sententico/cost/tel"
+)
+
+var (
+	decoder struct {
+		once sync.Once
+		d    tel.Decoder
+	}
+	raters struct {
+		sync.Mutex
+		m map[string]*tel.Rater
+	}
+)
+
+// telDecoder returns the shared E.164 decoder (loaded on first use, with NANP bias unless "nanp"
+// parameter is "0")
+func telDecoder() *tel.Decoder {
+	decoder.once.Do(func() {
+		decoder.d.NANPbias = pfax.Param("nanp") != "0"
+		if e := decoder.d.Load(nil); e != nil {
+			panic(e)
+		}
+	})
+	return &decoder.d
+}
+
+// telRater returns the shared rater for rate "deck" (loaded on first use): a rates file location,
+// or built-in "T1" or "T2" international or "NA" (default) North American termination rates
+func telRater(deck string) (*tel.Rater, error) {
+	raters.Lock()
+	defer raters.Unlock()
+	if r := raters.m[deck]; r != nil {
+		return r, nil
+	}
+	r := &tel.Rater{}
+	switch deck {
+	case "", "NA":
+	case "T1":
+		r.Default = tel.T1intlTermRates
+	case "T2":
+		r.Default = tel.T2intlTermRates
+	default:
+		r.Location = deck
+	}
+	if e := r.Load(nil); e != nil {
+		return nil, e
+	}
+	if raters.m == nil {
+		raters.m = make(map[string]*tel.Rater)
+	}
+	raters.m[deck] = r
+	return r, nil
+}
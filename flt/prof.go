@@ -0,0 +1,155 @@
+package flt
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/internal/sketch"
+)
+
+type (
+	// ProfCol column profile: value counts by inferred class ("int", "dec", "time:<layout>" or
+	// "str"), blank and E.164 number counts (of "+" prefixed or NANP-patterned values), value lengths, a distinct value estimate and first
+	// distinct values (up to ProfVals)
+	ProfCol struct {
+		N      int
+		Blank  int
+		E164   int
+		Class  map[string]int
+		MinLen int
+		MaxLen int
+		Card   *sketch.HLL
+		Vals   []string
+		More   bool // more than ProfVals distinct values
+	}
+
+	// Profile Prof results: column profiles (partial per file from Prof filters, merged by the Prof
+	// aggregator), with column heads in order
+	Profile struct {
+		Cols []string
+		Col  map[string]*ProfCol
+	}
+)
+
+// Prof constants
+const (
+	ProfVals = 12 // distinct values retained per column (samples or enumeration)
+	ProfPrec = 12 // HyperLogLog precision (~1.6% standard error)
+)
+
+// ProfLayouts time layouts recognized by Prof
+var ProfLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"01/02/2006 15:04:05",
+	"01/02/2006",
+	"1/2/2006",
+	"15:04:05",
+}
+
+// Prof concurrent filter; profiles CSV/fixed-field values per column
+func Prof(fin chan<- *Profile, in <-chan map[string]string, res csv.Resource) {
+	p := &Profile{Col: make(map[string]*ProfCol)}
+	for _, h := range res.Heads {
+		p.Cols, p.Col[h] = append(p.Cols, h), newProfCol()
+	}
+	for row := range in {
+		if _, ok := row["~meta"]; ok {
+			continue
+		}
+		for _, h := range p.Cols {
+			p.Col[h].add(row[h]) // columns missing from row are blank
+		}
+		for h, v := range row {
+			if p.Col[h] == nil && !strings.HasPrefix(h, "~") {
+				c := newProfCol()
+				p.Cols, p.Col[h] = append(p.Cols, h), c
+				c.add(v)
+			}
+		}
+	}
+	fin <- p
+}
+
+// newProfCol returns an empty column profile
+func newProfCol() *ProfCol {
+	return &ProfCol{Class: make(map[string]int), MinLen: -1, Card: sketch.NewHLL(ProfPrec)}
+}
+
+// add method on ProfCol profiles value "v"
+func (c *ProfCol) add(v string) {
+	c.N++
+	if v = strings.TrimSpace(v); v == "" {
+		c.Blank++
+		return
+	}
+	if l := utf8.RuneCountInString(v); c.MinLen < 0 || l < c.MinLen {
+		c.MinLen = l
+	}
+	if l := utf8.RuneCountInString(v); l > c.MaxLen {
+		c.MaxLen = l
+	}
+	c.Card.Add(v)
+	if !c.More {
+		i := 0
+		for ; i < len(c.Vals) && c.Vals[i] != v; i++ {
+		}
+		if i == len(c.Vals) && len(c.Vals) < ProfVals {
+			c.Vals = append(c.Vals, v)
+		} else if i == len(c.Vals) {
+			c.More = true
+		}
+	}
+	c.Class[valClass(v)]++
+	if telLike(v) && telDecoder().Digest(v) != 0 {
+		c.E164++
+	}
+}
+
+// telLike returns true if (trimmed, non-blank) value "v" is patterned as a telephone number: "+"
+// prefixed, or (with NANP bias) of 10 digits or 11 digits with leading 1, allowing "()-. " separators;
+// other digit strings (like account or record IDs) are not decoded
+func telLike(v string) bool {
+	n, lead := 0, rune(0)
+	for i, r := range v {
+		switch {
+		case r >= '0' && r <= '9':
+			if n++; n == 1 {
+				lead = r
+			}
+		case r == '+' && i == 0, strings.ContainsRune("()-. ", r):
+		default:
+			return false
+		}
+	}
+	switch {
+	case v[0] == '+':
+		return true
+	case !telDecoder().NANPbias:
+		return false
+	}
+	return n == 10 || n == 11 && lead == '1'
+}
+
+// valClass returns the value class of (trimmed, non-blank) value "v"
+func valClass(v string) string {
+	if _, e := strconv.ParseInt(v, 10, 64); e == nil {
+		return "int"
+	} else if _, e = strconv.ParseFloat(v, 64); e == nil {
+		return "dec"
+	}
+	if v[0] >= '0' && v[0] <= '9' {
+		for _, l := range ProfLayouts {
+			if _, e := time.Parse(l, v); e == nil {
+				return "time:" + l
+			}
+		}
+	}
+	return "str"
+}
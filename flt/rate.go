@@ -0,0 +1,214 @@
+package flt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/internal/pfax"
+	"github.com/sententico/cost/tel"
+)
+
+type (
+	// RateCols Rate filter column and rating defaults for a file format (overridden by parameters
+	// of the same name in lower case)
+	RateCols struct {
+		Num      string  // number column (or "+"-joined columns, such as country code and number)
+		Dur      string  // call duration column
+		Charge   string  // charged amount column (optional)
+		Unit     string  // duration unit ("s" default, "ms" or "min")
+		Deck     string  // rate deck (see telRater)
+		Metered  string  // metered duration column (in Unit) for BYOC detection (optional)
+		BYOC     float64 // charge per metered minute below which calls are zero-rated as BYOC
+		FX       float64 // currency conversion multiplier of rate deck amounts (1 if unset)
+		Currency string  // currency of charged and re-rated amounts ("USD" default)
+		Places   int     // re-rated amount decimal places, rounded up per call (4 if unset)
+	}
+
+	// RateGroup re-rating totals for a country and prefix
+	RateGroup struct {
+		Country string  // ISO 3166-2 alpha country code
+		Prefix  string  // E.164 country code and national-scope prefix
+		Calls   int     // calls rated
+		BYOC    int     // calls zero-rated as BYOC (included in Calls)
+		Mins    float64 // billed minutes (by billing increment)
+		Charged float64 // charged amounts
+		Rated   float64 // re-rated amounts
+	}
+
+	// RateFail describes a call that could not be re-rated
+	RateFail struct {
+		File   string
+		Line   int
+		Num    string
+		Reason string
+	}
+
+	// Rating Rate results (partial per file from Rate filters, merged by the Rate aggregator)
+	Rating struct {
+		Currency string                // amounts currency ("mixed" if files differ)
+		Groups   map[string]*RateGroup // by prefix
+		Fails    []RateFail            // first failures (up to "fails" parameter)
+		NFail    int                   // failed calls
+		FailChg  float64               // charged amounts of failed calls
+	}
+)
+
+// Rate returns concurrent filter re-rating calls of CSV/fixed-field CDR rows, decoding numbers
+// with a tel.Decoder and rating billed minutes with a tel.Rater (converted by FX), using "rc"
+// columns, rate deck and currency unless set by parameters; "incr" parameter sets the billing
+// increment; calls charged below the BYOC rate per metered minute are zero-rated
+func Rate(rc RateCols) func(chan<- *Rating, <-chan map[string]string, csv.Resource) {
+	return func(fin chan<- *Rating, in <-chan map[string]string, res csv.Resource) {
+		for p, v := range map[string]*string{"num": &rc.Num, "dur": &rc.Dur, "charge": &rc.Charge, "unit": &rc.Unit,
+			"deck": &rc.Deck, "metered": &rc.Metered, "currency": &rc.Currency} {
+			if s := pfax.Param(p); s != "" {
+				*v = s
+			}
+		}
+		for p, v := range map[string]*float64{"byoc": &rc.BYOC, "fx": &rc.FX} {
+			if s := pfax.Param(p); s != "" {
+				f, e := strconv.ParseFloat(s, 64)
+				if e != nil || f < 0 {
+					panic(fmt.Errorf("invalid %q parameter %q", p, s))
+				}
+				*v = f
+			}
+		}
+		if rc.FX == 0 {
+			rc.FX = 1
+		}
+		if rc.Currency == "" {
+			rc.Currency = "USD"
+		}
+		if rc.Places == 0 {
+			rc.Places = 4
+		}
+		scale := math.Pow10(rc.Places)
+		num, div := splitNum(rc.Num), 1.0
+		switch rc.Unit {
+		case "", "s":
+		case "ms":
+			div = 1000
+		case "min":
+			div = 1.0 / 60
+		default:
+			panic(fmt.Errorf("unknown duration unit %q", rc.Unit))
+		}
+		first, next := billIncr(pfax.Param("incr"))
+		maxFails, _ := strconv.Atoi(pfax.Param("fails"))
+		if len(num) == 0 || rc.Dur == "" {
+			panic(fmt.Errorf("no number/duration columns specified for %q", res.Location))
+		}
+		if rc.Metered != "" && rc.Charge == "" {
+			panic(fmt.Errorf("BYOC detection by %q requires a charge column", rc.Metered))
+		}
+		for _, h := range append(append([]string{rc.Dur}, num...), append(splitNum(rc.Charge), splitNum(rc.Metered)...)...) {
+			if !hasHead(res.Heads, h) {
+				panic(fmt.Errorf("column %q not found in %q", h, res.Location))
+			}
+		}
+		d, r := telDecoder(), (*tel.Rater)(nil)
+		if rr, e := telRater(rc.Deck); e != nil {
+			panic(fmt.Errorf("cannot load rate deck %q: %v", rc.Deck, e))
+		} else {
+			r = rr
+		}
+
+		var tn tel.E164full
+		rt := &Rating{Currency: rc.Currency, Groups: make(map[string]*RateGroup)}
+		fail := func(row map[string]string, n string, chg float64, reason string) {
+			if rt.NFail++; len(rt.Fails) < maxFails {
+				line, _ := strconv.Atoi(row["~line"])
+				rt.Fails = append(rt.Fails, RateFail{File: res.Location, Line: line, Num: n, Reason: reason})
+			}
+			rt.FailChg += chg
+		}
+		for row := range in {
+			if _, ok := row["~meta"]; ok {
+				continue
+			}
+			var b strings.Builder
+			for _, h := range num {
+				b.WriteString(row[h])
+			}
+			n, chg := b.String(), 0.0
+			if rc.Charge != "" {
+				chg, _ = strconv.ParseFloat(strings.TrimSpace(row[rc.Charge]), 64)
+			}
+			secs, e := strconv.ParseFloat(strings.TrimSpace(row[rc.Dur]), 64)
+			if e != nil || secs < 0 {
+				fail(row, n, chg, fmt.Sprintf("invalid duration %q", row[rc.Dur]))
+				continue
+			} else if e = d.Full(n, &tn); e != nil {
+				fail(row, n, chg, e.Error())
+				continue
+			}
+			byoc := false
+			if rc.Metered != "" {
+				m, _ := strconv.ParseFloat(strings.TrimSpace(row[rc.Metered]), 64)
+				byoc = m > 0 && chg/(m/div/60) < rc.BYOC
+			}
+			rate := r.Lookup(&tn)
+			if rate == 0 && !byoc {
+				fail(row, n, chg, fmt.Sprintf("no rate for +%s", tn.Num))
+				continue
+			}
+			mins := billSecs(secs/div, first, next) / 60
+			g := rt.Groups[tn.CC+tn.P]
+			if g == nil {
+				g = &RateGroup{Country: tn.ISO3166, Prefix: tn.CC + tn.P}
+				rt.Groups[g.Prefix] = g
+			}
+			g.Calls++
+			g.Mins += mins
+			g.Charged += chg
+			if byoc {
+				g.BYOC++ // zero-rated presumed BYOC call
+			} else {
+				g.Rated += math.Trunc(float64(rate)*mins*rc.FX*scale+0.999999999) / scale
+			}
+		}
+		fin <- rt
+	}
+}
+
+// splitNum returns columns of "+"-joined column list "s"
+func splitNum(s string) (cols []string) {
+	for _, h := range strings.Split(s, "+") {
+		if h = strings.TrimSpace(h); h != "" {
+			cols = append(cols, h)
+		}
+	}
+	return
+}
+
+// billIncr returns first and next billing increments (seconds) from "<first>[/<next>]" spec "s"
+// (per-second billing default)
+func billIncr(s string) (first, next float64) {
+	v := strings.SplitN(s, "/", 2)
+	first, _ = strconv.ParseFloat(v[0], 64)
+	if next = first; len(v) > 1 {
+		next, _ = strconv.ParseFloat(v[1], 64)
+	}
+	if first <= 0 {
+		first = 1
+	}
+	if next <= 0 {
+		next = 1
+	}
+	return
+}
+
+// billSecs returns billed seconds of a "secs" call by "first" and "next" billing increments
+func billSecs(secs, first, next float64) float64 {
+	switch {
+	case secs <= 0:
+		return 0
+	case secs <= first:
+		return first
+	}
+	return first + math.Ceil((secs-first)/next-1e-9)*next
+}
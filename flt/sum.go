@@ -0,0 +1,105 @@
+package flt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+type (
+	// SumGroup group-by sum totals for a key (key column values)
+	SumGroup struct {
+		Key  []string
+		Sums []float64
+		Rows int
+	}
+
+	// Sums group-by sum results: partial (per file) from Sum filters, merged by the Sum aggregator
+	Sums struct {
+		Keys   []string             // key column heads
+		Cols   []string             // summed column heads
+		Groups map[string]*SumGroup // groups by joined key values
+		Bad    int                  // non-numeric sum column values skipped
+		Skip   int                  // partial results skipped for inconsistent columns (aggregate)
+	}
+)
+
+// Sum returns concurrent filter summing numeric columns of CSV/fixed-field rows by group key;
+// summed columns are "sums" (comma-separated) unless set by "sums" parameter, and key columns are
+// set by "keys" parameter, or are the remaining column map columns
+func Sum(sums string) func(chan<- *Sums, <-chan map[string]string, csv.Resource) {
+	return func(fin chan<- *Sums, in <-chan map[string]string, res csv.Resource) {
+		if p := pfax.Param("sums"); p != "" {
+			sums = p
+		}
+		s := &Sums{Cols: splitHeads(sums), Groups: make(map[string]*SumGroup)}
+		if s.Keys = splitHeads(pfax.Param("keys")); len(s.Keys) == 0 {
+		nextHead:
+			for _, h := range res.Heads {
+				for _, c := range s.Cols {
+					if h == c {
+						continue nextHead
+					}
+				}
+				s.Keys = append(s.Keys, h)
+			}
+		}
+		if len(s.Cols) == 0 {
+			panic(fmt.Errorf("no sum columns specified for %q", res.Location))
+		}
+		for _, h := range append(s.Cols, s.Keys...) {
+			if !hasHead(res.Heads, h) {
+				panic(fmt.Errorf("column %q not found in %q", h, res.Location))
+			}
+		}
+
+		key := make([]string, len(s.Keys))
+		for row := range in {
+			if _, ok := row["~meta"]; ok {
+				continue
+			}
+			for i, h := range s.Keys {
+				key[i] = row[h]
+			}
+			k := strings.Join(key, "\x00")
+			g := s.Groups[k]
+			if g == nil {
+				g = &SumGroup{Key: append([]string(nil), key...), Sums: make([]float64, len(s.Cols))}
+				s.Groups[k] = g
+			}
+			for i, h := range s.Cols {
+				if v := strings.TrimSpace(row[h]); v == "" {
+				} else if f, e := strconv.ParseFloat(v, 64); e != nil {
+					s.Bad++
+				} else {
+					g.Sums[i] += f
+				}
+			}
+			g.Rows++
+		}
+		fin <- s
+	}
+}
+
+// splitHeads returns trimmed non-empty column heads from comma-separated list "s"
+func splitHeads(s string) (heads []string) {
+	for _, h := range strings.Split(s, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			heads = append(heads, h)
+		}
+	}
+	return
+}
+
+// hasHead returns true if "h" is in "heads"
+func hasHead(heads []string, h string) bool {
+	for _, hh := range heads {
+		if hh == h {
+			return true
+		}
+	}
+	return false
+}
@@ -1,31 +1,77 @@
-package flt
-
-import (
-	"strings"
-
-	"github.com/sententico/cost/csv"
-	_ "github.com/sententico/cost/internal/pfax" // stub reference
-)
-
-// WC concurrent filter; counts CSV/fixed-field values per column
-func WC(fin chan<- interface{}, in <-chan map[string]string, res csv.Resource) {
-	var ke map[string]int
-	km, ok := make(map[string]map[string]int), false
-	for row := range in {
-		for k, v := range row {
-			if strings.HasPrefix(k, "~") {
-				continue
-			}
-			if ke, ok = km[k]; !ok {
-				ke = make(map[string]int)
-				km[k] = ke
-			}
-			if _, ok = ke[v]; ok {
-				ke[v]++
-			} else if len(ke) < 2000 {
-				ke[v] = 1
-			}
-		}
-	}
-	fin <- km
-}
+package flt
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/internal/pfax"
+	"github.com/sententico/cost/internal/sketch"
+)
+
+// Counts WC results by column: exact value counts (up to "max" parameter distinct values), or in
+// sketch mode ("top" parameter set), top value frequency and cardinality estimates
+type Counts struct {
+	Vals map[string]map[string]int // exact value counts
+	Top  map[string]*sketch.TopK   // top value frequency estimates (sketch mode)
+	Card map[string]*sketch.HLL    // distinct value estimates (sketch mode)
+}
+
+// WC sketch mode constants
+const (
+	WCdelta = 0.01 // Count-Min error bound failure probability
+	WCprec  = 14   // HyperLogLog precision (~0.8% standard error)
+)
+
+// WC concurrent filter; counts CSV/fixed-field values per column
+func WC(fin chan<- *Counts, in <-chan map[string]string, res csv.Resource) {
+	if top, _ := strconv.Atoi(pfax.Param("top")); top > 0 {
+		eps, _ := strconv.ParseFloat(pfax.Param("eps"), 64)
+		fin <- wcSketch(in, top, eps)
+		return
+	}
+	var ke map[string]int
+	km, ok := make(map[string]map[string]int), false
+	max, _ := strconv.Atoi(pfax.Param("max"))
+	for row := range in {
+		for k, v := range row {
+			if strings.HasPrefix(k, "~") {
+				continue
+			}
+			if ke, ok = km[k]; !ok {
+				ke = make(map[string]int)
+				km[k] = ke
+			}
+			if _, ok = ke[v]; ok {
+				ke[v]++
+			} else if len(ke) < max {
+				ke[v] = 1
+			}
+		}
+	}
+	fin <- &Counts{Vals: km}
+}
+
+// wcSketch returns WC sketch-mode counts of "in" rows, tracking "top" values per column with
+// frequency estimates of "eps" relative error
+func wcSketch(in <-chan map[string]string, top int, eps float64) *Counts {
+	if eps <= 0 || eps >= 1 {
+		eps = 0.001
+	}
+	c := &Counts{Top: make(map[string]*sketch.TopK), Card: make(map[string]*sketch.HLL)}
+	for row := range in {
+		for k, v := range row {
+			if strings.HasPrefix(k, "~") {
+				continue
+			}
+			t, ok := c.Top[k]
+			if !ok {
+				t = sketch.NewTopK(top, eps, WCdelta)
+				c.Top[k], c.Card[k] = t, sketch.NewHLL(WCprec)
+			}
+			t.Add(v)
+			c.Card[k].Add(v)
+		}
+	}
+	return c
+}
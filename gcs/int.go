@@ -1 +1 @@
-package gcs
+package gcs
@@ -1,3 +1,5 @@
 module github.com/sententico/cost
 
 go 1.18
+
+require github.com/klauspost/compress v1.17.0
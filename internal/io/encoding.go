@@ -0,0 +1,199 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// xcoder is a reader transcoding content from "r" to UTF-8 using "conv", which appends converted
+// "src" to "dst" returning bytes consumed (all remaining bytes must be consumed at "eof"); input
+// and output buffers are reused across reads
+type xcoder struct {
+	r                   io.Reader
+	conv                func(dst, src []byte, eof bool) ([]byte, int)
+	ibuf, obuf, in, out []byte
+	err                 error
+}
+
+// encoding labels
+const (
+	encUTF8    = "utf-8"
+	encUTF16LE = "utf-16le"
+	encUTF16BE = "utf-16be"
+	encCP1252  = "windows-1252"
+	encLatin1  = "iso-8859-1"
+)
+
+var (
+	// cp1252 maps Windows-1252 runes in the 0x80-0x9f range (C1 controls in ISO-8859-1)
+	cp1252 = [32]rune{
+		'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+		'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+	}
+)
+
+// Decode returns a reader transcoding "r" content to UTF-8 from encoding "enc", or from an encoding
+// detected by byte-order mark or content (UTF-8, UTF-16LE/BE or Windows-1252) if "enc" is blank,
+// with the encoding label; invalid UTF-8 bytes (as in Windows-1252 content beyond the detection
+// sample) are read as Windows-1252
+func Decode(r io.ReadCloser, enc string) (io.ReadCloser, string, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	head, _ := br.Peek(4096)
+	switch enc = strings.ToLower(enc); {
+	case bytes.HasPrefix(head, []byte{0xef, 0xbb, 0xbf}) && (enc == "" || enc == encUTF8):
+		br.Discard(3)
+		enc = encUTF8
+	case bytes.HasPrefix(head, []byte{0xff, 0xfe}) && (enc == "" || enc == encUTF16LE):
+		br.Discard(2)
+		enc = encUTF16LE
+	case bytes.HasPrefix(head, []byte{0xfe, 0xff}) && (enc == "" || enc == encUTF16BE):
+		br.Discard(2)
+		enc = encUTF16BE
+	case enc != "":
+	case nulRatio(head, 1) > 0.25:
+		enc = encUTF16LE
+	case nulRatio(head, 0) > 0.25:
+		enc = encUTF16BE
+	case validUTF8(head):
+		enc = encUTF8
+	default:
+		enc = encCP1252
+	}
+
+	x := &xcoder{r: br}
+	switch enc {
+	case encUTF8, "utf8":
+		x.conv, enc = convUTF8, encUTF8
+	case encUTF16LE:
+		x.conv = func(dst, src []byte, eof bool) ([]byte, int) { return convUTF16(dst, src, false) }
+	case encUTF16BE:
+		x.conv = func(dst, src []byte, eof bool) ([]byte, int) { return convUTF16(dst, src, true) }
+	case encCP1252, "cp1252":
+		x.conv, enc = convCP1252, encCP1252
+	case encLatin1, "latin-1", "latin1":
+		x.conv, enc = convCP1252, encLatin1
+	default:
+		return nil, "", fmt.Errorf("unsupported encoding %q", enc)
+	}
+	return &codecCloser{Reader: x, src: r}, enc, nil
+}
+
+// Read method on xcoder reads transcoded content
+func (x *xcoder) Read(p []byte) (n int, err error) {
+	for len(x.out) == 0 {
+		if x.err != nil {
+			return 0, x.err
+		}
+		if x.ibuf == nil {
+			x.ibuf = make([]byte, 32<<10)
+		}
+		k := copy(x.ibuf, x.in) // pending partial rune
+		n, x.err = x.r.Read(x.ibuf[k:])
+		x.in = x.ibuf[:k+n]
+		x.out, n = x.conv(x.obuf[:0], x.in, x.err != nil)
+		x.obuf, x.in = x.out, x.in[n:]
+	}
+	n = copy(p, x.out)
+	x.out = x.out[n:]
+	return n, nil
+}
+
+// convUTF8 appends valid UTF-8 runes in "src" to "dst", reading invalid bytes as Windows-1252
+func convUTF8(dst, src []byte, eof bool) ([]byte, int) {
+	i := 0
+	for i < len(src) {
+		if src[i] < utf8.RuneSelf {
+			j := i + 1
+			for ; j < len(src) && src[j] < utf8.RuneSelf; j++ {
+			}
+			dst, i = append(dst, src[i:j]...), j // ASCII run
+			continue
+		}
+		switch r, size := utf8.DecodeRune(src[i:]); {
+		case r != utf8.RuneError || size > 1:
+			dst, i = append(dst, src[i:i+size]...), i+size
+		case !eof && !utf8.FullRune(src[i:]):
+			return dst, i // incomplete rune pending more input
+		default:
+			dst, i = utf8.AppendRune(dst, cp1252Rune(src[i])), i+1
+		}
+	}
+	return dst, i
+}
+
+// convUTF16 appends UTF-8 encoded runes of UTF-16 "src" (big-endian if "be") to "dst"
+func convUTF16(dst, src []byte, be bool) ([]byte, int) {
+	i := 0
+	for ; i+1 < len(src); i += 2 {
+		u := uint16(src[i]) | uint16(src[i+1])<<8
+		if be {
+			u = uint16(src[i])<<8 | uint16(src[i+1])
+		}
+		if !utf16.IsSurrogate(rune(u)) {
+			dst = utf8.AppendRune(dst, rune(u))
+			continue
+		} else if i+3 >= len(src) {
+			break // surrogate pair pending more input
+		}
+		l := uint16(src[i+2]) | uint16(src[i+3])<<8
+		if be {
+			l = uint16(src[i+2])<<8 | uint16(src[i+3])
+		}
+		if r := utf16.DecodeRune(rune(u), rune(l)); r != utf8.RuneError {
+			dst, i = utf8.AppendRune(dst, r), i+2
+		}
+	}
+	return dst, i
+}
+
+// convCP1252 appends UTF-8 encoded runes of Windows-1252 (or ISO-8859-1) "src" to "dst"
+func convCP1252(dst, src []byte, eof bool) ([]byte, int) {
+	for _, b := range src {
+		if b < utf8.RuneSelf {
+			dst = append(dst, b)
+		} else {
+			dst = utf8.AppendRune(dst, cp1252Rune(b))
+		}
+	}
+	return dst, len(src)
+}
+
+// cp1252Rune returns the rune mapped to Windows-1252 byte "b"
+func cp1252Rune(b byte) rune {
+	if b >= 0x80 && b < 0xa0 {
+		return cp1252[b-0x80]
+	}
+	return rune(b)
+}
+
+// nulRatio returns the ratio of NUL bytes at even (or odd if "odd") offsets in "b"
+func nulRatio(b []byte, odd int) float64 {
+	n, z := 0, 0
+	for i := odd; i < len(b); i += 2 {
+		if n++; b[i] == 0 {
+			z++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(z) / float64(n)
+}
+
+// validUTF8 returns true if "b" is valid UTF-8 (ignoring an incomplete final rune)
+func validUTF8(b []byte) bool {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				b = b[:i]
+			}
+			break
+		}
+	}
+	return utf8.Valid(b)
+}
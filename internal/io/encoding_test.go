@@ -0,0 +1,62 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecode(t *testing.T) {
+	ascii := strings.Repeat("1,plain ascii row\n", 500) // beyond the detection sample
+	tests := []struct {
+		name, enc string
+		in        []byte
+		want      string
+		label     string
+	}{
+		{"UTF-8", "", []byte("a,José\n"), "a,José\n", encUTF8},
+		{"UTF-8 BOM", "", []byte("\xef\xbb\xbfa,€\n"), "a,€\n", encUTF8},
+		{"Latin-1 after sample", "", []byte(ascii + "999,Jos\xe9 M\xfcller\n"), ascii + "999,José Müller\n", encUTF8},
+		{"mixed after sample", "", []byte(ascii + "9,été \x80\x93\n"), ascii + "9,été €“\n", encUTF8},
+		{"Windows-1252", "", []byte("a,Jos\xe9 \x93q\x94\n"), "a,José “q”\n", encCP1252},
+		{"UTF-16LE BOM", "", []byte("\xff\xfea\x00,\x00\xe9\x00\n\x00"), "a,é\n", encUTF16LE},
+		{"UTF-16BE BOM", "", []byte("\xfe\xff\x00a\x00,\xd8\x3d\xde\x00\x00\n"), "a,\U0001f600\n", encUTF16BE},
+		{"UTF-16LE detected", "", []byte("a\x00b\x00c\x00\n\x00"), "abc\n", encUTF16LE},
+		{"Latin-1 specified", "latin1", []byte("a,\xe9\n"), "a,é\n", encLatin1},
+	}
+	for _, tt := range tests {
+		// runes split across reads must be reassembled
+		for _, one := range []bool{false, true} {
+			var src io.Reader = bytes.NewReader(tt.in)
+			if one {
+				src = iotest.OneByteReader(src)
+			}
+			r, label, e := Decode(io.NopCloser(src), tt.enc)
+			if e != nil {
+				t.Fatalf("%s: %v", tt.name, e)
+			}
+			b, e := io.ReadAll(r)
+			switch {
+			case e != nil:
+				t.Errorf("%s: %v", tt.name, e)
+			case label != tt.label:
+				t.Errorf("%s: encoding %q, want %q", tt.name, label, tt.label)
+			case string(b) != tt.want:
+				t.Errorf("%s (byte reads %v): read %q, want %q", tt.name, one, tail(string(b)), tail(tt.want))
+			}
+		}
+	}
+	if _, _, e := Decode(io.NopCloser(strings.NewReader("a")), "ebcdic"); e == nil {
+		t.Errorf("unsupported encoding accepted")
+	}
+}
+
+// tail returns the end of long string "s" for error reporting
+func tail(s string) string {
+	if len(s) > 40 {
+		return "..." + s[len(s)-40:]
+	}
+	return s
+}
@@ -1,56 +1,135 @@
-package io
-
-import (
-	"bufio"
-	"fmt"
-	"io"
-	"os/user"
-	"path/filepath"
-	"strings"
-)
-
-// ReadLn returns a channel into which a goroutine writes text lines from an io.Reader (channels
-// also provided for initial peek-ahead lines, errors and for the consumer to signal a halt)
-func ReadLn(r io.Reader, peekLines int) (<-chan string, <-chan string, <-chan error, chan<- int) {
-	peek, out, err, sig := make(chan string, peekLines), make(chan string, 64), make(chan error, 1), make(chan int)
-	go func() {
-		defer func() {
-			if e := recover(); e != nil {
-				err <- e.(error)
-			}
-			close(err)
-			close(out)
-		}()
-		ln := bufio.NewScanner(r) // bufio.ReadString('\n') may handle EOF better with io.Pipe input
-
-		for len(out) < cap(peek) && len(out) < cap(out) && ln.Scan() {
-			peek <- ln.Text()
-			out <- ln.Text() // assumes out not processed until peek closed
-		}
-		for close(peek); ln.Scan(); {
-			select {
-			case out <- ln.Text():
-			case <-sig:
-				return
-			}
-		}
-		if e := ln.Err(); e != nil {
-			panic(fmt.Errorf("error scanning lines (%v)", e))
-		}
-	}()
-	return peek, out, err, sig
-}
-
-// ResolveName is a helper function that resolves resource names (pathnames, ...)
-func ResolveName(n string) string {
-	if strings.HasPrefix(n, "~/") {
-		if u, e := user.Current(); e == nil {
-			if rn, e := filepath.Abs(u.HomeDir + n[1:]); e == nil {
-				return rn
-			}
-		}
-	} else if rn, e := filepath.Abs(n); e == nil {
-		return rn
-	}
-	return n
-}
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// codecCloser pairs a decompressing reader with its (optional) release function and the
+// underlying compressed source
+type codecCloser struct {
+	io.Reader
+	release func()
+	src     io.Closer
+}
+
+// Close method on codecCloser releases decompressor resources and closes the compressed source
+func (c *codecCloser) Close() error {
+	if c.release != nil {
+		c.release()
+	}
+	return c.src.Close()
+}
+
+// ReadLn returns a channel into which a goroutine writes text lines from an io.Reader (channels
+// also provided for initial peek-ahead lines, errors and for the consumer to signal a halt)
+func ReadLn(r io.Reader, peekLines int) (<-chan string, <-chan string, <-chan error, chan<- int) {
+	ln := bufio.NewScanner(r) // bufio.ReadString('\n') may handle EOF better with io.Pipe input
+	return readScan(ln, peekLines, ln.Text)
+}
+
+// ReadRec returns a channel into which a goroutine writes logical RFC 4180 records (with source
+// positions) from an io.Reader, joining text lines (with "\n") while a double-quote enclosure
+// opened at the start of a field (separated by any rune in "seps") remains open; other channels
+// are as for ReadLn
+func ReadRec(r io.Reader, peekLines int, seps string) (<-chan string, <-chan Rec, <-chan error, chan<- int) {
+	rs := &recScanner{ln: bufio.NewScanner(r), seps: seps}
+	rs.ln.Split(rs.split)
+	return readScan(rs, peekLines, rs.Rec)
+}
+
+// ReadLnRec returns a channel into which a goroutine writes text lines as records (with source
+// positions) from an io.Reader, without joining lines (as for JSON Lines, where double-quotes
+// don't enclose fields); other channels are as for ReadLn
+func ReadLnRec(r io.Reader, peekLines int) (<-chan string, <-chan Rec, <-chan error, chan<- int) {
+	rs := &recScanner{ln: bufio.NewScanner(r), lines: true}
+	rs.ln.Split(rs.split)
+	return readScan(rs, peekLines, rs.Rec)
+}
+
+// scanner is the line/record scanning interface shared by bufio.Scanner and recScanner
+type scanner interface {
+	Scan() bool
+	Text() string
+	Err() error
+}
+
+// readScan returns a channel into which a goroutine writes items scanned from "ln" (channels also
+// provided for initial peek-ahead text, errors and for the consumer to signal a halt)
+func readScan[T any](ln scanner, peekLines int, item func() T) (<-chan string, <-chan T, <-chan error, chan<- int) {
+	peek, out, err, sig := make(chan string, peekLines), make(chan T, 64), make(chan error, 1), make(chan int)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				err <- e.(error)
+			}
+			close(err)
+			close(out)
+		}()
+		for len(out) < cap(peek) && len(out) < cap(out) && ln.Scan() {
+			peek <- ln.Text()
+			out <- item() // assumes out not processed until peek closed
+		}
+		for close(peek); ln.Scan(); {
+			select {
+			case out <- item():
+			case <-sig:
+				return
+			}
+		}
+		if e := ln.Err(); e != nil {
+			panic(fmt.Errorf("error scanning lines (%v)", e))
+		}
+	}()
+	return peek, out, err, sig
+}
+
+// Decompress returns a reader transparently decompressing gzip, bzip2 or zstd content from "r"
+// with the name of the codec identified ("" if uncompressed); codecs are identified by magic
+// bytes, with "name" suffixes (.gz, .bz2, .zst) only resolving weak bzip2 signatures
+func Decompress(r io.ReadCloser, name string) (io.ReadCloser, string, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	magic, _ := br.Peek(10)
+	switch ext := strings.ToLower(filepath.Ext(name)); {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b, 0x08}):
+		zr, e := gzip.NewReader(br)
+		if e != nil {
+			return nil, "", fmt.Errorf("gzip content problem (%v)", e)
+		}
+		return &codecCloser{Reader: zr, release: func() { zr.Close() }, src: r}, "gzip", nil
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		zr, e := zstd.NewReader(br)
+		if e != nil {
+			return nil, "", fmt.Errorf("zstd content problem (%v)", e)
+		}
+		return &codecCloser{Reader: zr, release: zr.Close, src: r}, "zstd", nil
+	case len(magic) >= 4 && bytes.HasPrefix(magic, []byte("BZh")) && magic[3] >= '1' && magic[3] <= '9' && (ext == ".bz2" ||
+		ext == ".bz" || bytes.HasPrefix(magic[4:], []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}) ||
+		bytes.HasPrefix(magic[4:], []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90})):
+		return &codecCloser{Reader: bzip2.NewReader(br), src: r}, "bzip2", nil
+	}
+	return &codecCloser{Reader: br, src: r}, "", nil
+}
+
+// ResolveName is a helper function that resolves resource names (pathnames, ...)
+func ResolveName(n string) string {
+	if strings.HasPrefix(n, "~/") {
+		if u, e := user.Current(); e == nil {
+			if rn, e := filepath.Abs(u.HomeDir + n[1:]); e == nil {
+				return rn
+			}
+		}
+	} else if rn, e := filepath.Abs(n); e == nil {
+		return rn
+	}
+	return n
+}
@@ -1,101 +1,222 @@
-package io
-
-type state uint8
-
-const (
-	stSEP state = iota
-	stENCL
-	stESC
-	stPFX
-	stIFX
-	stSFX
-)
-
-// SliceCSV returns buffer with blank-trimmed field slices for "csv" split by "sep", using a safe
-// but tolerant implementation of RFC 4180
-func SliceCSV(csv string, sep rune, expected int) ([]byte, []uint16) {
-	if len(csv) > 0xffff {
-		csv = csv[:0xffff]
-	}
-	if expected > len(csv) {
-		expected = len(csv) + 1
-	}
-	var slen uint16
-	buf, sl, st := make([]byte, 0, len(csv)-expected+1), make([]uint16, 1, expected+1), stSEP
-
-	for _, r := range csv {
-		if r > '\x7e' || r != '\x09' && r < '\x20' {
-			continue // all non-printable ASCII runes dropped
-		}
-
-		switch st {
-		case stSEP:
-			switch r {
-			case sep: // separator state
-				sl = append(sl, uint16(len(buf)))
-			case '"':
-				st = stENCL
-			case ' ':
-				st = stPFX
-			default:
-				buf, st = append(buf, byte(r)), stIFX
-			}
-		case stENCL: // double-quote enclosure state (ingests until closing double-quote)
-			switch r {
-			case '"':
-				st = stESC
-			default:
-				buf = append(buf, byte(r))
-			}
-		case stESC: // double-quote single-rune escape state (any rune but separator escaped)
-			switch r {
-			case sep:
-				sl, st = append(sl, uint16(len(buf))), stSEP
-			default:
-				buf, st = append(buf, byte(r)), stENCL
-			}
-		case stPFX: // unenclosed prefix state (leading blanks skipped)
-			switch r {
-			case sep:
-				sl, st = append(sl, uint16(len(buf))), stSEP
-			case ' ':
-			default:
-				buf, st = append(buf, byte(r)), stIFX
-			}
-		case stIFX: // unenclosed infix state (ingests until blank/separator)
-			switch r {
-			case sep:
-				sl, st = append(sl, uint16(len(buf))), stSEP
-			case ' ':
-				buf, slen, st = append(buf, byte(r)), uint16(len(buf)), stSFX
-			default:
-				buf = append(buf, byte(r))
-			}
-		case stSFX: // unenclosed suffix state (final blanks deleted)
-			switch r {
-			case sep:
-				sl, buf, st = append(sl, uint16(slen)), buf[:slen], stSEP
-			case ' ':
-				buf = append(buf, byte(r))
-			default:
-				buf, st = append(buf, byte(r)), stIFX
-			}
-		}
-	}
-
-	if st == stSFX {
-		return buf, append(sl, slen)
-	}
-	return buf, append(sl, uint16(len(buf)))
-}
-
-// SplitCSV returns blank-trimmed fields in "csv" split by "sep", using a safe but tolerant
-// implementation of RFC 4180
-func SplitCSV(csv string, sep rune) []string {
-	buf, sl := SliceCSV(csv, sep, 1+len(csv)/4)
-	fields := make([]string, 0, len(sl))
-	for i := 1; i < len(sl); i++ {
-		fields = append(fields, string(buf[sl[i-1]:sl[i]]))
-	}
-	return fields
-}
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type state uint8
+
+// Rec is a logical record with its source position
+type Rec struct {
+	Text string // record text (joined lines)
+	Line int    // source line number of record
+	Off  int64  // source byte offset of record
+	End  int64  // source byte offset following record (and line terminator)
+}
+
+// recScanner joins lines from a bufio.Scanner into logical RFC 4180 records (or scans unjoined
+// lines as records)
+type recScanner struct {
+	ln       *bufio.Scanner
+	seps     string
+	lines    bool
+	rec      Rec
+	line     int
+	off, pos int64
+}
+
+// maxRec is the record length beyond which an open enclosure is presumed unmatched (SliceCSV limit)
+const maxRec = 0xffff
+
+const (
+	stSEP state = iota
+	stENCL
+	stESC
+	stPFX
+	stIFX
+	stSFX
+)
+
+// SliceCSV returns buffer with blank-trimmed field slices for "csv" split by "sep", using a safe
+// but tolerant implementation of RFC 4180 (UTF-8 preserved)
+func SliceCSV(csv string, sep rune, expected int) ([]byte, []uint16) {
+	if len(csv) > 0xffff {
+		csv = csv[:0xffff]
+	}
+	if expected > len(csv) {
+		expected = len(csv) + 1
+	}
+	var slen uint16
+	buf, sl, st := make([]byte, 0, len(csv)-expected+1), make([]uint16, 1, expected+1), stSEP
+
+	for _, r := range csv {
+		if r == utf8.RuneError || r != '\x09' && !unicode.IsGraphic(r) && (r != '\n' || st != stENCL) {
+			continue // all non-printable runes dropped (but for enclosed newlines)
+		}
+
+		switch st {
+		case stSEP:
+			switch r {
+			case sep: // separator state
+				sl = append(sl, uint16(len(buf)))
+			case '"':
+				st = stENCL
+			case ' ':
+				st = stPFX
+			default:
+				buf, st = utf8.AppendRune(buf, r), stIFX
+			}
+		case stENCL: // double-quote enclosure state (ingests until closing double-quote)
+			switch r {
+			case '"':
+				st = stESC
+			default:
+				buf = utf8.AppendRune(buf, r)
+			}
+		case stESC: // double-quote single-rune escape state (any rune but separator escaped)
+			switch r {
+			case sep:
+				sl, st = append(sl, uint16(len(buf))), stSEP
+			default:
+				buf, st = utf8.AppendRune(buf, r), stENCL
+			}
+		case stPFX: // unenclosed prefix state (leading blanks skipped)
+			switch r {
+			case sep:
+				sl, st = append(sl, uint16(len(buf))), stSEP
+			case ' ':
+			default:
+				buf, st = utf8.AppendRune(buf, r), stIFX
+			}
+		case stIFX: // unenclosed infix state (ingests until blank/separator)
+			switch r {
+			case sep:
+				sl, st = append(sl, uint16(len(buf))), stSEP
+			case ' ':
+				buf, slen, st = utf8.AppendRune(buf, r), uint16(len(buf)), stSFX
+			default:
+				buf = utf8.AppendRune(buf, r)
+			}
+		case stSFX: // unenclosed suffix state (final blanks deleted)
+			switch r {
+			case sep:
+				sl, buf, st = append(sl, uint16(slen)), buf[:slen], stSEP
+			case ' ':
+				buf = utf8.AppendRune(buf, r)
+			default:
+				buf, st = utf8.AppendRune(buf, r), stIFX
+			}
+		}
+	}
+
+	if st == stSFX {
+		return buf, append(sl, slen)
+	}
+	return buf, append(sl, uint16(len(buf)))
+}
+
+// SplitCSV returns blank-trimmed fields in "csv" split by "sep", using a safe but tolerant
+// implementation of RFC 4180
+func SplitCSV(csv string, sep rune) []string {
+	buf, sl := SliceCSV(csv, sep, 1+len(csv)/4)
+	fields := make([]string, 0, len(sl))
+	for i := 1; i < len(sl); i++ {
+		fields = append(fields, string(buf[sl[i-1]:sl[i]]))
+	}
+	return fields
+}
+
+// Scan method on recScanner advances to the next logical record, joining lines while an enclosure
+// remains open (until maxRec exceeded)
+func (rs *recScanner) Scan() bool {
+	if rs.off = rs.pos; !rs.ln.Scan() {
+		return false
+	}
+	rs.line++
+	rs.rec = Rec{Text: rs.ln.Text(), Line: rs.line, Off: rs.off}
+	for encl := !rs.lines && enclosed(rs.rec.Text, rs.seps, false); encl && len(rs.rec.Text) < maxRec && rs.ln.Scan(); rs.line++ {
+		ln := rs.ln.Text()
+		rs.rec.Text, encl = rs.rec.Text+"\n"+ln, enclosed(ln, rs.seps, true)
+	}
+	rs.rec.End = rs.pos
+	return true
+}
+
+// split method on recScanner is a bufio.ScanLines wrapper tracking source byte position
+func (rs *recScanner) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	advance, token, err = bufio.ScanLines(data, atEOF)
+	rs.pos += int64(advance)
+	return
+}
+
+// Text method on recScanner returns the current logical record text
+func (rs *recScanner) Text() string {
+	return rs.rec.Text
+}
+
+// Rec method on recScanner returns the current logical record
+func (rs *recScanner) Rec() Rec {
+	return rs.rec
+}
+
+// Err method on recScanner returns the first non-EOF error encountered scanning lines
+func (rs *recScanner) Err() error {
+	return rs.ln.Err()
+}
+
+// RecEnd returns the offset in "b" (beginning at a record boundary) following its last complete
+// logical RFC 4180 record and line terminator as joined by ReadRec with separators "seps" (0 if
+// no record is complete)
+func RecEnd(b []byte, seps string) (end int) {
+	for start, pos, encl := 0, 0, false; pos < len(b); {
+		i := bytes.IndexByte(b[pos:], '\n')
+		if i < 0 {
+			break
+		}
+		ln := bytes.TrimSuffix(b[pos:pos+i], []byte{'\r'})
+		if encl = enclosed(ln, seps, encl); !encl || pos+i-start >= maxRec {
+			start, end, encl = pos+i+1, pos+i+1, false
+		}
+		pos += i + 1
+	}
+	return
+}
+
+// ScanRec calls "fn" with each logical RFC 4180 record (as for ReadRec) read from "r", with source
+// positions following "line" and "off", until "fn" returns false or input is exhausted
+func ScanRec(r io.Reader, seps string, line int, off int64, fn func(Rec) bool) error {
+	rs := &recScanner{ln: bufio.NewScanner(r), seps: seps, line: line, pos: off}
+	for rs.ln.Split(rs.split); rs.Scan(); {
+		if !fn(rs.rec) {
+			return nil
+		}
+	}
+	return rs.Err()
+}
+
+// enclosed returns true if a double-quote enclosure remains open at the end of "ln" given its
+// state at the beginning; enclosures open only at field starts (following any rune in "seps")
+func enclosed[T string | []byte](ln T, seps string, encl bool) bool {
+	start := !encl
+	for i := 0; i < len(ln); i++ {
+		switch c := ln[i]; {
+		case encl && c == '"' && i+1 < len(ln) && ln[i+1] == '"':
+			i++ // escaped double-quote
+		case encl && c == '"':
+			encl, start = false, false
+		case encl:
+		case c == '"' && start:
+			encl = true
+		case strings.IndexByte(seps, c) >= 0:
+			start = true
+		case c != ' ':
+			start = false
+		}
+	}
+	return encl
+}
@@ -0,0 +1,114 @@
+package io
+
+import (
+	"strings"
+	"testing"
+)
+
+// scanAll returns records of "s" scanned by ScanRec from "line" and "off"
+func scanAll(t *testing.T, s, seps string, line int, off int64) []Rec {
+	t.Helper()
+	var recs []Rec
+	if e := ScanRec(strings.NewReader(s), seps, line, off, func(r Rec) bool {
+		recs = append(recs, r)
+		return true
+	}); e != nil {
+		t.Fatal(e)
+	}
+	return recs
+}
+
+func TestScanRec(t *testing.T) {
+	tests := []struct {
+		name, in, seps string
+		want           []Rec
+	}{
+		{"plain", "a,b\nc,d\n", ",", []Rec{
+			{Text: "a,b", Line: 1, Off: 0, End: 4},
+			{Text: "c,d", Line: 2, Off: 4, End: 8},
+		}},
+		{"multi-line", "h1,h2\n1,\"x\ny\"\n2,z", ",", []Rec{
+			{Text: "h1,h2", Line: 1, Off: 0, End: 6},
+			{Text: "1,\"x\ny\"", Line: 2, Off: 6, End: 14},
+			{Text: "2,z", Line: 4, Off: 14, End: 17},
+		}},
+		{"CRLF escaped", "a,\"say \"\"hi\r\nthere\"\"\"\r\nb,c\r\n", ",", []Rec{
+			{Text: "a,\"say \"\"hi\nthere\"\"\"", Line: 1, Off: 0, End: 23},
+			{Text: "b,c", Line: 3, Off: 23, End: 28},
+		}},
+		{"mid-field quote", "a,b\"c\nd,e\n", ",", []Rec{
+			{Text: "a,b\"c", Line: 1, Off: 0, End: 6},
+			{Text: "d,e", Line: 2, Off: 6, End: 10},
+		}},
+		{"separator set", "a|\"b\nc\"\nd\n", "|", []Rec{
+			{Text: "a|\"b\nc\"", Line: 1, Off: 0, End: 8},
+			{Text: "d", Line: 3, Off: 8, End: 10},
+		}},
+	}
+	for _, tt := range tests {
+		recs := scanAll(t, tt.in, tt.seps, 0, 0)
+		if len(recs) != len(tt.want) {
+			t.Errorf("%s: %d records %q, want %d", tt.name, len(recs), recs, len(tt.want))
+			continue
+		}
+		for i, r := range recs {
+			if r != tt.want[i] {
+				t.Errorf("%s record %d: %+v, want %+v", tt.name, i, r, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRecEndChunks(t *testing.T) {
+	in := "id,note,amt\r\n" +
+		"1,plain,1.00\r\n" +
+		"2,\"two\nlines\",2.00\n" +
+		"3,\"quoted \"\"comma,\"\" here\",3.00\r\n" +
+		"4,\"three\r\nline\n\"\"record\"\"\",4.00\n" +
+		"5,\"\",5.00\n" +
+		"6,\"\"\"\nopen\",6.00\n" +
+		"7,last,7.00"
+	full := scanAll(t, in, ",", 0, 0)
+	if len(full) != 8 {
+		t.Fatalf("%d records, want 8", len(full))
+	}
+
+	// every chunk boundary must end at the last complete record, and scanning resumed from there
+	// must reproduce the remaining records with their source positions
+	for k := 0; k <= len(in); k++ {
+		want, line, next := 0, 0, 0
+		for i, r := range full {
+			if int(r.End) <= k && in[r.End-1] == '\n' {
+				want, next = int(r.End), i+1
+				line = full[i].Line + strings.Count(r.Text, "\n")
+			}
+		}
+		end := RecEnd([]byte(in[:k]), ",")
+		if end != want {
+			t.Fatalf("chunk of %d bytes: RecEnd %d, want %d", k, end, want)
+		}
+		rest := scanAll(t, in[end:], ",", line, int64(end))
+		if len(rest) != len(full)-next {
+			t.Fatalf("chunk of %d bytes: resumed %d records, want %d", k, len(rest), len(full)-next)
+		}
+		for i, r := range rest {
+			if r != full[next+i] {
+				t.Fatalf("chunk of %d bytes: resumed record %+v, want %+v", k, r, full[next+i])
+			}
+		}
+	}
+}
+
+func TestRecEndUnmatched(t *testing.T) {
+	// an unmatched enclosure is abandoned after maxRec bytes, as ReadRec joins it
+	in := "a,\"open\n" + strings.Repeat("x,y\n", maxRec/4+2) + "b,c\n"
+	recs, end := scanAll(t, in, ",", 0, 0), RecEnd([]byte(in), ",")
+	if last := recs[len(recs)-1]; end != len(in) || last.Text != "b,c" || last.End != int64(len(in)) {
+		t.Errorf("RecEnd %d (of %d), last record %+v", end, len(in), last)
+	}
+	for _, r := range recs[:len(recs)-1] {
+		if RecEnd([]byte(in[:r.End]), ",") != int(r.End) {
+			t.Errorf("record ending at %d not a RecEnd boundary", r.End)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package pfax
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sententico/cost/csv"
+)
+
+// Join lookup-join stage: rows are enriched with the non-key columns of the lookup table row
+// (loaded from a CSV resource) matching on key columns, before filtering; row columns take
+// precedence over lookup columns of the same name
+type Join struct {
+	Location string   // lookup table resource location
+	Keys     []string // row key columns
+	LKeys    []string // lookup table key columns (corresponding to Keys)
+	Cols     []string // lookup columns appended to rows (set on Load)
+
+	table map[string]map[string]string
+	mutex sync.Mutex
+	tally
+}
+
+// tally counts rows joined and unmatched (by key) by a Join
+type tally struct {
+	rows      int
+	unmatched map[string]int
+	more      int // unmatched rows of keys not tracked
+}
+
+// Joins ...
+type Joins []*Join
+
+// joinTrack is the limit of distinct unmatched keys tracked per join
+const joinTrack = 10000
+
+// Load method on Join reads its lookup table through a Resource using "settings" cache.
+func (j *Join) Load(settings *csv.Settings) (e error) {
+	res := csv.Resource{Location: j.Location, SettingsCache: settings}
+	if e = res.Open(nil); e != nil {
+		return fmt.Errorf("error opening lookup %q: %v", j.Location, e)
+	}
+	defer res.Close()
+	for _, h := range j.LKeys {
+		if !hasCol(res.Heads, h) {
+			return fmt.Errorf("lookup %q has no %q key column", j.Location, h)
+		}
+	}
+	for _, h := range res.Heads {
+		if !hasCol(j.LKeys, h) {
+			j.Cols = append(j.Cols, h)
+		}
+	}
+
+	j.table, j.unmatched = make(map[string]map[string]string), make(map[string]int)
+	key := make([]string, len(j.LKeys))
+	in, err := res.Get()
+	for row := range in {
+		if _, ok := row["~meta"]; ok {
+			continue
+		}
+		for i, h := range j.LKeys {
+			key[i] = strings.TrimSpace(row[h])
+		}
+		if k := strings.Join(key, "\x00"); j.table[k] == nil {
+			lr := make(map[string]string, len(j.Cols))
+			for _, h := range j.Cols {
+				if v, ok := row[h]; ok {
+					lr[h] = v
+				}
+			}
+			j.table[k] = lr
+		}
+	}
+	if e = <-err; e != nil {
+		return fmt.Errorf("error reading lookup %q: %v", j.Location, e)
+	}
+	return nil
+}
+
+// enrich method on Join appends lookup columns to "row" if its key matches (counted in stage tally
+// "t"), returning false if not
+func (j *Join) enrich(row map[string]string, key []string, t *tally) bool {
+	for i, h := range j.Keys {
+		key[i] = strings.TrimSpace(row[h])
+	}
+	k := strings.Join(key, "\x00")
+	lr, ok := j.table[k]
+	if ok {
+		for h, v := range lr {
+			if _, ok := row[h]; !ok {
+				row[h] = v
+			}
+		}
+	}
+	if t.rows++; !ok {
+		t.track(k, 1)
+	}
+	return ok
+}
+
+// track method on tally counts "n" unmatched rows of key "k" (untracked beyond joinTrack keys)
+func (t *tally) track(k string, n int) {
+	if _, ok := t.unmatched[k]; ok || len(t.unmatched) < joinTrack {
+		t.unmatched[k] += n
+	} else {
+		t.more += n
+	}
+}
+
+// merge method on Join adds stage tally "t" to its counts
+func (j *Join) merge(t *tally) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.rows, j.more = j.rows+t.rows, j.more+t.more
+	for k, n := range t.unmatched {
+		j.track(k, n)
+	}
+}
+
+// Report method on Join writes its unmatched row and key counts (with the most frequent
+// unmatched keys) to "w".
+func (j *Join) Report(w io.Writer) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	n := j.more
+	keys := make([]string, 0, len(j.unmatched))
+	for k, c := range j.unmatched {
+		keys, n = append(keys, k), n+c
+	}
+	fmt.Fprintf(w, "lookup %q [%s]: %d of %d rows unmatched", j.Location, strings.Join(j.Keys, ","), n, j.rows)
+	if len(keys) == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if j.unmatched[keys[a]] != j.unmatched[keys[b]] {
+			return j.unmatched[keys[a]] > j.unmatched[keys[b]]
+		}
+		return keys[a] < keys[b]
+	})
+	if j.more > 0 {
+		fmt.Fprintf(w, " (over %d keys); most frequent:", len(keys))
+	} else {
+		fmt.Fprintf(w, " (%d keys); most frequent:", len(keys))
+	}
+	for i, k := range keys {
+		if i == 5 {
+			break
+		}
+		fmt.Fprintf(w, " %q (%d)", strings.ReplaceAll(k, "\x00", ","), j.unmatched[k])
+	}
+	fmt.Fprintln(w)
+}
+
+// stage method on Joins returns a channel into which a goroutine writes "in" rows enriched by
+// each Join until "in" is exhausted or "done" is closed (as when a filter returns early); match
+// counts are tallied locally and then merged into each Join
+func (js Joins) stage(in <-chan map[string]string, done <-chan struct{}) <-chan map[string]string {
+	if len(js) == 0 {
+		return in
+	}
+	out := make(chan map[string]string, 64)
+	go func() {
+		defer close(out)
+		keys, tallies := make([][]string, len(js)), make([]tally, len(js))
+		for i, j := range js {
+			keys[i], tallies[i].unmatched = make([]string, len(j.Keys)), make(map[string]int)
+		}
+		defer func() {
+			for i, j := range js {
+				j.merge(&tallies[i])
+			}
+		}()
+		for row := range in {
+			if _, ok := row["~meta"]; !ok {
+				for i, j := range js {
+					j.enrich(row, keys[i], &tallies[i])
+				}
+			}
+			select {
+			case out <- row:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// heads method on Joins returns "heads" with lookup columns of each Join appended
+func (js Joins) heads(heads []string) []string {
+	heads = append([]string(nil), heads...)
+	for _, j := range js {
+		for _, h := range j.Cols {
+			if !hasCol(heads, h) {
+				heads = append(heads, h)
+			}
+		}
+	}
+	return heads
+}
+
+// String method...
+func (js *Joins) String() string {
+	var s []string
+	for _, j := range *js {
+		kv := make([]string, len(j.Keys))
+		for i, k := range j.Keys {
+			if kv[i] = k; j.LKeys[i] != k {
+				kv[i] += "=" + j.LKeys[i]
+			}
+		}
+		s = append(s, j.Location+":"+strings.Join(kv, ","))
+	}
+	return strings.Join(s, " ")
+}
+
+// Set method...
+func (js *Joins) Set(value string) error {
+	i := strings.LastIndexByte(value, ':')
+	if i <= 0 || i == len(value)-1 {
+		return fmt.Errorf("lookup join must be <file>:<key>[=<lookup key>][,...]")
+	}
+	j := &Join{Location: value[:i]}
+	for _, kv := range strings.Split(value[i+1:], ",") {
+		k := strings.SplitN(kv, "=", 2)
+		if k[0] = strings.TrimSpace(k[0]); k[0] == "" {
+			return fmt.Errorf("empty lookup join key")
+		}
+		if len(k) == 1 {
+			k = append(k, k[0])
+		}
+		j.Keys, j.LKeys = append(j.Keys, k[0]), append(j.LKeys, strings.TrimSpace(k[1]))
+	}
+	*js = append(*js, j)
+	return nil
+}
+
+// hasCol returns true if "h" is in "heads"
+func hasCol(heads []string, h string) bool {
+	for _, hh := range heads {
+		if hh == h {
+			return true
+		}
+	}
+	return false
+}
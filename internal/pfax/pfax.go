@@ -1,54 +1,277 @@
-package pfax
-
-import (
-	"fmt"
-
-	"github.com/sententico/cost/csv"
-)
-
-// Fentry ...
-type Fentry struct {
-	Flt  func(chan<- interface{}, <-chan map[string]string, csv.Resource)
-	Cols string
-}
-
-// Fmap ...
-type Fmap map[string]Fentry
-
-// Xentry ...
-type Xentry struct {
-	Descr string
-	Xfm   func(interface{})
-	Agg   func(<-chan interface{}) interface{}
-	Fm    Fmap
-}
-
-// Xmap ...
-type Xmap map[string]Xentry
-
-// Xname ...
-type Xname string
-
-var (
-	// Args ...
-	Args struct {
-		XfmFlag      Xname
-		SettingsFlag string
-	}
-	// Xm ...
-	Xm Xmap
-)
-
-// String method...
-func (x *Xname) String() string {
-	return string(*x)
-}
-
-// Set method...
-func (x *Xname) Set(value string) error {
-	if _, ok := Xm[value]; !ok {
-		return fmt.Errorf("unknown transform")
-	}
-	*x = Xname(value)
-	return nil
-}
+package pfax
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sententico/cost/csv"
+)
+
+// Fentry filter entry for a file format; filter sends partial results of type F for aggregation
+type Fentry[F any] struct {
+	Flt  func(chan<- F, <-chan map[string]string, csv.Resource)
+	Cols string
+}
+
+// Fmap filter entries by file format ("*" for any)
+type Fmap[F any] map[string]Fentry[F]
+
+// Xparam describes a transform parameter
+type Xparam struct {
+	Name    string
+	Default string
+	Descr   string
+}
+
+// Pipeline flt/agg/xfm triple: filters (by file format) send partial results of type F to an
+// aggregator producing an aggregate of type A for transformation; pairing is checked at compile time
+type Pipeline[F, A any] struct {
+	Descr  string
+	Fm     Fmap[F]
+	Agg    func(<-chan F) A
+	Xfm    func(A)
+	XfmW   func(map[int64]A) // windowed transform (optional; windows keyed by Unix epoch start seconds)
+	Params []Xparam
+}
+
+// Xentry registered transform pipeline, independent of its partial result and aggregate types
+type Xentry interface {
+	Describe() (descr string, formats []string, params []Xparam)
+	Run(locs []string, settings *csv.Settings)
+}
+
+// Xmap ...
+type Xmap map[string]Xentry
+
+// Xname ...
+type Xname string
+
+// Xparams ...
+type Xparams map[string]string
+
+var (
+	// Args ...
+	Args struct {
+		XfmFlag      Xname
+		SettingsFlag string
+		ListFlag     bool
+		Params       Xparams
+		Joins        Joins
+		Window       Window
+	}
+	// Xm ...
+	Xm Xmap
+)
+
+// Register adds transform pipeline "name" to the registry (Xm); transform packages register their
+// pipelines on initialization, panicking if incomplete or already registered.
+func Register[F, A any](name string, p *Pipeline[F, A]) {
+	if Xm == nil {
+		Xm = make(Xmap)
+	}
+	switch {
+	case Xm[name] != nil:
+		panic(fmt.Errorf("transform %q already registered", name))
+	case p.Agg == nil || p.Xfm == nil || len(p.Fm) == 0:
+		panic(fmt.Errorf("transform %q incomplete", name))
+	}
+	Xm[name] = p
+}
+
+// Param returns the value of parameter "name" of the selected transform (its default if unset).
+func Param(name string) string {
+	if v, ok := Args.Params[name]; ok {
+		return v
+	}
+	if x := Xm[string(Args.XfmFlag)]; x != nil {
+		_, _, params := x.Describe()
+		for _, p := range params {
+			if p.Name == name {
+				return p.Default
+			}
+		}
+	}
+	return ""
+}
+
+// Check returns an error if the selected transform is unknown or given parameters it doesn't
+// define.
+func Check() error {
+	x := Xm[string(Args.XfmFlag)]
+	if x == nil {
+		return fmt.Errorf("unknown transform %q", Args.XfmFlag)
+	}
+	_, _, params := x.Describe()
+	for n := range Args.Params {
+		i := 0
+		for ; i < len(params) && params[i].Name != n; i++ {
+		}
+		if i == len(params) {
+			return fmt.Errorf("transform %q has no %q parameter", Args.XfmFlag, n)
+		}
+	}
+	return nil
+}
+
+// List writes registered transform names, descriptions, supported file formats and parameters
+// to "w".
+func List(w io.Writer) {
+	names := make([]string, 0, len(Xm))
+	for n := range Xm {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		descr, fmts, params := Xm[n].Describe()
+		fmt.Fprintf(w, "%s\t%s\n\tformats: %s\n", n, descr, strings.Join(fmts, ", "))
+		for _, p := range params {
+			fmt.Fprintf(w, "\t-p %s=<value>\t%s (default %q)\n", p.Name, p.Descr, p.Default)
+		}
+	}
+}
+
+// Describe method on Pipeline returns its description, supported file formats and parameters
+func (p *Pipeline[F, A]) Describe() (descr string, formats []string, params []Xparam) {
+	for f := range p.Fm {
+		if f == "*" {
+			f = "* (any)"
+		}
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return p.Descr, formats, p.Params
+}
+
+// Run method on Pipeline filters files matching "locs" concurrently (choosing filters by their
+// settings format, with rows enriched by any lookup Joins), aggregating partial results for
+// transformation; rows are aggregated by time window if Args.Window specifies a timestamp column
+func (p *Pipeline[F, A]) Run(locs []string, settings *csv.Settings) {
+	if Args.Window.Col != "" {
+		p.runWindowed(locs, settings)
+		return
+	}
+	var wg sync.WaitGroup
+	fin := make(chan F, 64)
+	p.each(locs, &wg, func(fn string) {
+		done := make(chan struct{})
+		res, fe, in, err := p.open(fn, settings, done)
+		defer res.Close()
+		defer close(done)
+
+		fe.Flt(fin, in, *res)
+		if e := <-err; e != nil {
+			panic(fmt.Errorf("%v", e))
+		}
+	})
+	go func() {
+		defer close(fin)
+		wg.Wait()
+	}()
+	p.Xfm(p.Agg(fin))
+}
+
+// each method on Pipeline calls "do" concurrently for each file matching "locs" (added to "wg"),
+// printing any panic
+func (p *Pipeline[F, A]) each(locs []string, wg *sync.WaitGroup, do func(string)) {
+	for _, loc := range locs {
+		files, _ := filepath.Glob(loc)
+		if len(files) == 0 {
+			files = []string{loc}
+		}
+		for _, file := range files {
+			wg.Add(1)
+
+			go func(fn string) {
+				defer func() {
+					if e := recover(); e != nil {
+						fmt.Printf("%v\n", e)
+					}
+					wg.Done()
+				}()
+				do(fn)
+			}(file)
+		}
+	}
+}
+
+// open method on Pipeline opens file "fn" as a Resource, returning it with its filter entry (by
+// settings format) and row/error channels from Get (rows enriched by any lookup Joins until "done"
+// is closed); panics on errors, including required "cols" or join key columns missing (checked
+// before rows are read)
+func (p *Pipeline[F, A]) open(fn string, settings *csv.Settings, done <-chan struct{}, cols ...string) (*csv.Resource, Fentry[F], <-chan map[string]string, <-chan error) {
+	var (
+		res = &csv.Resource{Location: fn, SettingsCache: settings}
+		fe  Fentry[F]
+		ok  bool
+	)
+	if e := res.Open(nil); e != nil {
+		panic(fmt.Errorf("error opening %q: %v", fn, e))
+	}
+	if fe, ok = p.Fm[res.Settings.Format]; !ok {
+		if fe, ok = p.Fm["*"]; !ok {
+			res.Close()
+			panic(fmt.Errorf("no filter defined for %q [%v]", fn, res.Settings.Format))
+		}
+	}
+	if fe.Cols != "" {
+		res.SetCols(fe.Cols)
+	}
+	for _, h := range cols {
+		if !hasCol(res.Heads, h) {
+			res.Close()
+			panic(fmt.Errorf("column %q not found in %q", h, fn))
+		}
+	}
+	for _, j := range Args.Joins {
+		for _, h := range j.Keys {
+			if !hasCol(res.Heads, h) {
+				res.Close()
+				panic(fmt.Errorf("lookup %q key column %q not found in %q", j.Location, h, fn))
+			}
+		}
+	}
+	in, err := res.Get()
+	in, res.Heads = Args.Joins.stage(in, done), Args.Joins.heads(res.Heads)
+	return res, fe, in, err
+}
+
+// String method...
+func (x *Xname) String() string {
+	return string(*x)
+}
+
+// Set method...
+func (x *Xname) Set(value string) error {
+	if Xm[value] == nil {
+		return fmt.Errorf("unknown transform")
+	}
+	*x = Xname(value)
+	return nil
+}
+
+// String method...
+func (p *Xparams) String() string {
+	var s []string
+	for n, v := range *p {
+		s = append(s, n+"="+v)
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+// Set method...
+func (p *Xparams) Set(value string) error {
+	v := strings.SplitN(value, "=", 2)
+	if len(v) != 2 || v[0] == "" {
+		return fmt.Errorf("parameter must be <name>=<value>")
+	}
+	if *p == nil {
+		*p = make(Xparams)
+	}
+	(*p)[v[0]] = v[1]
+	return nil
+}
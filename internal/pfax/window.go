@@ -0,0 +1,220 @@
+package pfax
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sententico/cost/csv"
+)
+
+// Window time-window stage: rows are bucketed by their timestamp column into hour, day or month
+// windows, each filtered and aggregated separately; windows are keyed by their start time in Unix
+// epoch seconds (window boundaries in Loc, with hours repeated at a DST fall-back windowed apart)
+type Window struct {
+	Col    string         // timestamp column (no windowing if unset)
+	Period string         // window period: "hour", "day" (default) or "month"
+	Layout string         // timestamp layout (TimeLayouts or 10-digit Unix epoch seconds detected if unset)
+	Loc    *time.Location // time zone of window boundaries and of timestamps without one (UTC default)
+
+	skipped int64
+}
+
+// TimeLayouts are window timestamp layouts recognized when unspecified
+var TimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"01/02/2006 15:04:05",
+	"01/02/2006",
+	"1/2/2006",
+}
+
+// Start method on Window returns the window key (start time in Unix epoch seconds) of timestamp
+// "v", returning false if it cannot be parsed.
+func (w *Window) Start(v string) (int64, bool) {
+	var t time.Time
+	loc, e := w.Loc, error(nil)
+	if loc == nil {
+		loc = time.UTC
+	}
+	switch v = strings.TrimSpace(v); {
+	case v == "":
+		return 0, false
+	case w.Layout != "":
+		t, e = time.ParseInLocation(w.Layout, v, loc)
+	default:
+		if s, ie := strconv.ParseInt(v, 10, 64); ie == nil && len(v) == 10 {
+			t = time.Unix(s, 0)
+			break
+		}
+		e = fmt.Errorf("unrecognized timestamp")
+		for _, l := range TimeLayouts {
+			if t, e = time.ParseInLocation(l, v, loc); e == nil {
+				break
+			}
+		}
+	}
+	if e != nil {
+		return 0, false
+	}
+	switch t = t.In(loc); w.Period {
+	case "hour": // truncated in elapsed time, so repeated hours at DST fall-back are distinct windows
+		t = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case "month":
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+	return t.Unix(), true
+}
+
+// Label method on Window returns the display label of window "k" (as keyed by Start).
+func (w *Window) Label(k int64) string {
+	loc := w.Loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	t := time.Unix(k, 0).In(loc)
+	switch w.Period {
+	case "hour":
+		return t.Format("2006-01-02 15:00 MST")
+	case "month":
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
+
+// Check method on Window returns an error if its period is invalid.
+func (w *Window) Check() error {
+	switch w.Period {
+	case "", "hour", "day", "month":
+		return nil
+	}
+	return fmt.Errorf("window period must be hour, day or month")
+}
+
+// Windows returns the ordered keys of windowed results "m".
+func Windows[A any](m map[int64]A) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// winOpen is the limit of windows per file with filters running; rows of another window close the
+// least recently routed filter (re-started for rows of its window that follow)
+const winOpen = 4
+
+// runWindowed method on Pipeline filters files matching "locs" concurrently like Run, but with
+// rows of each file routed by Args.Window to a filter per window (up to winOpen at once), and
+// filtered results of each window aggregated separately; windowed aggregates are passed to XfmW (or
+// to Xfm per window)
+func (p *Pipeline[F, A]) runWindowed(locs []string, settings *csv.Settings) {
+	type win struct {
+		fin chan F
+		agg chan A
+	}
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		w     = &Args.Window
+	)
+	wins := make(map[int64]*win)
+	getWin := func(k int64) *win {
+		mutex.Lock()
+		defer mutex.Unlock()
+		wn := wins[k]
+		if wn == nil {
+			wn = &win{fin: make(chan F, 64), agg: make(chan A, 1)}
+			wins[k] = wn
+			go func() { wn.agg <- p.Agg(wn.fin) }()
+		}
+		return wn
+	}
+
+	p.each(locs, &wg, func(fn string) {
+		done := make(chan struct{})
+		res, fe, in, err := p.open(fn, settings, done, w.Col)
+		defer res.Close()
+		defer close(done)
+		var fwg sync.WaitGroup
+		ins, used, seq := make(map[int64]chan map[string]string), make(map[int64]int), 0
+		for row := range in {
+			if _, ok := row["~meta"]; ok {
+				continue
+			}
+			k, ok := w.Start(row[w.Col])
+			if !ok {
+				atomic.AddInt64(&w.skipped, 1)
+				continue
+			}
+			if ins[k] == nil {
+				if len(ins) == winOpen {
+					lru := k
+					for ok := range ins {
+						if lru == k || used[ok] < used[lru] {
+							lru = ok
+						}
+					}
+					close(ins[lru])
+					delete(ins, lru)
+				}
+				wi, wn := make(chan map[string]string, 64), getWin(k)
+				ins[k] = wi
+				fwg.Add(1)
+				go func() {
+					defer func() {
+						if e := recover(); e != nil {
+							fmt.Printf("%v\n", e)
+							for range wi {
+							}
+						}
+						fwg.Done()
+					}()
+					fe.Flt(wn.fin, wi, *res)
+				}()
+			}
+			seq++
+			used[k] = seq
+			ins[k] <- row
+		}
+		for _, wi := range ins {
+			close(wi)
+		}
+		fwg.Wait()
+		if e := <-err; e != nil {
+			panic(fmt.Errorf("%v", e))
+		}
+	})
+	wg.Wait()
+
+	m := make(map[int64]A, len(wins))
+	for k, wn := range wins {
+		close(wn.fin)
+		m[k] = <-wn.agg
+	}
+	if n := atomic.LoadInt64(&w.skipped); n > 0 {
+		fmt.Fprintf(os.Stderr, "%d rows skipped for invalid %q timestamps\n", n, w.Col)
+	}
+	if p.XfmW != nil {
+		p.XfmW(m)
+		return
+	}
+	for i, k := range Windows(m) {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("[%s]\n", w.Label(k))
+		p.Xfm(m[k])
+	}
+}
@@ -0,0 +1,117 @@
+package sketch
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// centroid is a t-digest cluster of values with mean "m" and weight "w"
+type centroid struct {
+	m, w float64
+}
+
+// topHeap is a min-heap (by Count) of TopK items indexed by value
+type topHeap struct {
+	items []TopItem
+	idx   map[string]int
+}
+
+func (h topHeap) Len() int           { return len(h.items) }
+func (h topHeap) Less(i, j int) bool { return h.items[i].Count < h.items[j].Count }
+func (h topHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.idx[h.items[i].Val], h.idx[h.items[j].Val] = i, j
+}
+func (h *topHeap) Push(x interface{}) {
+	it := x.(TopItem)
+	h.idx[it.Val] = len(h.items)
+	h.items = append(h.items, it)
+}
+func (h *topHeap) Pop() interface{} {
+	it := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	delete(h.idx, it.Val)
+	return it
+}
+
+// hash2 returns two independent 64-bit hashes of "s" (FNV-1a, with splitmix64 finalization)
+func hash2(s string) (h1, h2 uint64) {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return mix(h), mix(h ^ 0x9e3779b97f4a7c15)
+}
+
+// mix returns splitmix64 finalization of "h"
+func mix(h uint64) uint64 {
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// index method on CountMin returns row "i" counter index for hashes "h1", "h2" (double hashing)
+func (cm *CountMin) index(h1, h2 uint64, i int) int {
+	return int((h1 + uint64(i)*h2) % uint64(cm.w))
+}
+
+// count method on CountMin returns the estimated count of a value with hashes "h1", "h2"
+func (cm *CountMin) count(h1, h2 uint64) (est uint64) {
+	for i := 0; i < cm.d; i++ {
+		if c := cm.c[i*cm.w+cm.index(h1, h2, i)]; i == 0 || c < est {
+			est = c
+		}
+	}
+	return
+}
+
+// rho returns the 1-based position of the leftmost 1-bit of "w"
+func rho(w uint64) uint8 {
+	return uint8(bits.LeadingZeros64(w) + 1)
+}
+
+// alpha returns the HyperLogLog bias-correction constant for "m" registers
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// compress method on TDigest merges buffered values into centroids, limiting centroid weight by
+// the k1 scale function (k(q) = Delta/2pi * asin(2q-1)) so tail centroids stay small
+func (td *TDigest) compress() {
+	if len(td.buf) == 0 {
+		return
+	}
+	all := append(td.c, td.buf...)
+	sort.Slice(all, func(i, j int) bool { return all[i].m < all[j].m })
+	k := func(q float64) float64 { return td.Delta / (2 * math.Pi) * math.Asin(2*q-1) }
+	kInv := func(k float64) float64 {
+		if k >= td.Delta/4 {
+			return 1
+		}
+		return (math.Sin(2*math.Pi*k/td.Delta) + 1) / 2
+	}
+	out, wsum := []centroid{all[0]}, 0.0
+	lim := kInv(k(0) + 1)
+	for _, x := range all[1:] {
+		cur := &out[len(out)-1]
+		if (wsum+cur.w+x.w)/td.N <= lim {
+			cur.m += (x.m - cur.m) * x.w / (cur.w + x.w)
+			cur.w += x.w
+		} else {
+			wsum += cur.w
+			lim = kInv(k(wsum/td.N) + 1)
+			out = append(out, x)
+		}
+	}
+	td.c, td.buf = out, td.buf[:0]
+}
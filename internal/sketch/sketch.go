@@ -0,0 +1,314 @@
+package sketch
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+type (
+	// CountMin is a Count-Min sketch estimating value frequencies in bounded space: estimates never
+	// undercount, and overcount by at most Eps times total count with probability 1-Delta
+	CountMin struct {
+		Eps   float64 // relative error bound (of total count)
+		Delta float64 // error bound failure probability
+		N     uint64  // total count added
+
+		w, d int
+		c    []uint64
+	}
+
+	// TopK tracks the K most frequent values (heavy hitters) estimated by a Count-Min sketch
+	TopK struct {
+		K  int       // values tracked
+		CM *CountMin // frequency estimates
+
+		h topHeap
+	}
+
+	// TopItem is a TopK value with its estimated count; its true count is within Err below Count
+	// (with probability 1-Delta)
+	TopItem struct {
+		Val   string
+		Count uint64
+		Err   uint64
+	}
+
+	// TDigest is a mergeable t-digest estimating quantiles of a stream of values from weighted
+	// centroids (clustered near the tails for quantile accuracy); merged digests are as accurate
+	TDigest struct {
+		Delta float64 // compression (retains about Delta centroids)
+		N     float64 // total weight added
+		Min   float64 // minimum value added
+		Max   float64 // maximum value added
+
+		c   []centroid
+		buf []centroid
+	}
+
+	// HLL is a HyperLogLog distinct-value (cardinality) estimator with 2^P registers
+	HLL struct {
+		P uint8 // precision (register index bits, 4-16)
+
+		reg []uint8
+	}
+)
+
+// NewCountMin returns a Count-Min sketch with "eps" relative error bound and "delta" failure
+// probability.
+func NewCountMin(eps, delta float64) *CountMin {
+	w, d := int(math.Ceil(math.E/eps)), int(math.Ceil(math.Log(1/delta)))
+	if d < 1 {
+		d = 1
+	}
+	return &CountMin{Eps: eps, Delta: delta, w: w, d: d, c: make([]uint64, w*d)}
+}
+
+// Add method on CountMin adds "n" to the count of value "s" (by conservative update, raising
+// only counters below the new estimate), returning its new estimated count.
+func (cm *CountMin) Add(s string, n uint64) uint64 {
+	h1, h2 := hash2(s)
+	est := cm.count(h1, h2) + n
+	for i := 0; i < cm.d; i++ {
+		if c := &cm.c[i*cm.w+cm.index(h1, h2, i)]; *c < est {
+			*c = est
+		}
+	}
+	cm.N += n
+	return est
+}
+
+// Count method on CountMin returns the estimated count of value "s".
+func (cm *CountMin) Count(s string) uint64 {
+	return cm.count(hash2(s))
+}
+
+// Err method on CountMin returns the estimate overcount bound (with probability 1-Delta).
+func (cm *CountMin) Err() uint64 {
+	return uint64(math.Ceil(cm.Eps * float64(cm.N)))
+}
+
+// Merge method on CountMin adds counts of "o" (of identical dimensions), returning false if
+// dimensions differ.
+func (cm *CountMin) Merge(o *CountMin) bool {
+	if cm.w != o.w || cm.d != o.d {
+		return false
+	}
+	for i, c := range o.c {
+		cm.c[i] += c
+	}
+	cm.N += o.N
+	return true
+}
+
+// NewTopK returns a TopK tracker of "k" values with frequencies estimated by a Count-Min sketch
+// of "eps" relative error bound and "delta" failure probability.
+func NewTopK(k int, eps, delta float64) *TopK {
+	return &TopK{K: k, CM: NewCountMin(eps, delta), h: topHeap{idx: make(map[string]int)}}
+}
+
+// Add method on TopK adds an occurrence of value "s".
+func (t *TopK) Add(s string) {
+	t.offer(s, t.CM.Add(s, 1))
+}
+
+// Merge method on TopK merges "o" (of identical sketch dimensions) into its tracked values,
+// re-estimating candidates from both against the merged sketch; returns false if dimensions
+// differ.
+func (t *TopK) Merge(o *TopK) bool {
+	if !t.CM.Merge(o.CM) {
+		return false
+	}
+	cand := make([]string, 0, len(t.h.items)+len(o.h.items))
+	for _, it := range t.h.items {
+		cand = append(cand, it.Val)
+	}
+	for _, it := range o.h.items {
+		cand = append(cand, it.Val)
+	}
+	t.h = topHeap{idx: make(map[string]int)}
+	for _, s := range cand {
+		t.offer(s, t.CM.Count(s))
+	}
+	return true
+}
+
+// Items method on TopK returns tracked values with estimated counts and error bounds, in
+// descending count order.
+func (t *TopK) Items() []TopItem {
+	items, err := make([]TopItem, 0, len(t.h.items)), t.CM.Err()
+	for _, it := range t.h.items {
+		e := err
+		if e > it.Count {
+			e = it.Count
+		}
+		items = append(items, TopItem{Val: it.Val, Count: it.Count, Err: e})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Val < items[j].Val
+	})
+	return items
+}
+
+// NewTDigest returns a t-digest of "delta" compression (100 typical).
+func NewTDigest(delta float64) *TDigest {
+	if delta < 10 {
+		delta = 10
+	}
+	return &TDigest{Delta: delta, Min: math.Inf(1), Max: math.Inf(-1)}
+}
+
+// Add method on TDigest adds value "x" (ignoring NaN).
+func (td *TDigest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	td.N, td.Min, td.Max = td.N+1, math.Min(td.Min, x), math.Max(td.Max, x)
+	if td.buf = append(td.buf, centroid{x, 1}); len(td.buf) >= int(5*td.Delta) {
+		td.compress()
+	}
+}
+
+// Merge method on TDigest adds centroids of "o".
+func (td *TDigest) Merge(o *TDigest) {
+	if o.N == 0 {
+		return
+	}
+	td.buf = append(append(td.buf, o.c...), o.buf...)
+	td.N, td.Min, td.Max = td.N+o.N, math.Min(td.Min, o.Min), math.Max(td.Max, o.Max)
+	td.compress()
+}
+
+// Quantile method on TDigest returns the estimated value at quantile "q" (0-1), or NaN if empty.
+func (td *TDigest) Quantile(q float64) float64 {
+	if td.compress(); len(td.c) == 0 {
+		return math.NaN()
+	}
+	switch t := q * td.N; {
+	case q <= 0:
+		return td.Min
+	case q >= 1:
+		return td.Max
+	case t < td.c[0].w/2:
+		return td.Min + (td.c[0].m-td.Min)*t/(td.c[0].w/2)
+	default:
+		cum := td.c[0].w / 2
+		for i := 1; i < len(td.c); i++ {
+			next := cum + (td.c[i-1].w+td.c[i].w)/2
+			if t < next {
+				return td.c[i-1].m + (td.c[i].m-td.c[i-1].m)*(t-cum)/(next-cum)
+			}
+			cum = next
+		}
+		last := td.c[len(td.c)-1]
+		return last.m + (td.Max-last.m)*math.Min(1, (t-cum)/(last.w/2))
+	}
+}
+
+// CDF method on TDigest returns the estimated fraction of values at or below "x", or NaN if empty.
+func (td *TDigest) CDF(x float64) float64 {
+	if td.compress(); len(td.c) == 0 {
+		return math.NaN()
+	}
+	switch {
+	case x < td.Min:
+		return 0
+	case x >= td.Max:
+		return 1
+	case x < td.c[0].m && td.c[0].w == 1:
+		return 0
+	case x < td.c[0].m:
+		return td.c[0].w / 2 / td.N * (x - td.Min) / math.Max(td.c[0].m-td.Min, math.SmallestNonzeroFloat64)
+	}
+	cum := td.c[0].w / 2
+	for i := 1; i < len(td.c); i++ {
+		l, r := td.c[i-1], td.c[i]
+		if x < r.m {
+			a, b := 0.0, 0.0 // single-value centroid halves are point masses (not interpolated)
+			if l.w == 1 {
+				a = 0.5
+			}
+			if r.w == 1 {
+				b = 0.5
+			}
+			return (cum + a + ((l.w+r.w)/2-a-b)*(x-l.m)/(r.m-l.m)) / td.N
+		}
+		cum += (l.w + r.w) / 2
+	}
+	last := td.c[len(td.c)-1]
+	return (cum + last.w/2*(x-last.m)/math.Max(td.Max-last.m, math.SmallestNonzeroFloat64)) / td.N
+}
+
+// NewHLL returns a HyperLogLog estimator of precision "p" (clamped to 4-16; standard error is
+// about 1.04/sqrt(2^p)).
+func NewHLL(p uint8) *HLL {
+	switch {
+	case p < 4:
+		p = 4
+	case p > 16:
+		p = 16
+	}
+	return &HLL{P: p, reg: make([]uint8, 1<<p)}
+}
+
+// Add method on HLL adds value "s".
+func (hl *HLL) Add(s string) {
+	h, _ := hash2(s)
+	i, r := h>>(64-hl.P), rho(h<<hl.P|1<<(hl.P-1))
+	if r > hl.reg[i] {
+		hl.reg[i] = r
+	}
+}
+
+// Count method on HLL returns the estimated number of distinct values added.
+func (hl *HLL) Count() uint64 {
+	m, sum, zeros := float64(len(hl.reg)), 0.0, 0
+	for _, r := range hl.reg {
+		if sum += math.Ldexp(1, -int(r)); r == 0 {
+			zeros++
+		}
+	}
+	est := alpha(len(hl.reg)) * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros)) // linear counting small-range correction
+	}
+	return uint64(est + 0.5)
+}
+
+// Err method on HLL returns the standard error of the Count estimate.
+func (hl *HLL) Err() uint64 {
+	return uint64(math.Ceil(1.04 / math.Sqrt(float64(len(hl.reg))) * float64(hl.Count())))
+}
+
+// Merge method on HLL merges registers of "o" (of identical precision), returning false if
+// precision differs.
+func (hl *HLL) Merge(o *HLL) bool {
+	if hl.P != o.P {
+		return false
+	}
+	for i, r := range o.reg {
+		if r > hl.reg[i] {
+			hl.reg[i] = r
+		}
+	}
+	return true
+}
+
+// offer method on TopK tracks value "s" with estimated count "est" if among the K most frequent
+func (t *TopK) offer(s string, est uint64) {
+	switch i, ok := t.h.idx[s]; {
+	case ok:
+		t.h.items[i].Count = est
+		heap.Fix(&t.h, i)
+	case len(t.h.items) < t.K:
+		heap.Push(&t.h, TopItem{Val: s, Count: est})
+	case t.K > 0 && est > t.h.items[0].Count:
+		delete(t.h.idx, t.h.items[0].Val)
+		t.h.items[0] = TopItem{Val: s, Count: est}
+		t.h.idx[s] = 0
+		heap.Fix(&t.h, 0)
+	}
+}
@@ -0,0 +1,278 @@
+package sketch
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// zipf returns a stream of "n" Zipf-distributed values (of "v" distinct) with their true counts
+func zipf(seed int64, n int, v uint64) ([]string, map[string]uint64) {
+	z := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.2, 1, v-1)
+	vals, counts := make([]string, n), make(map[string]uint64)
+	for i := range vals {
+		vals[i] = "v" + strconv.FormatUint(z.Uint64(), 10)
+		counts[vals[i]]++
+	}
+	return vals, counts
+}
+
+// ranks returns the fractions of sorted "xs" below and at or below "x"
+func ranks(xs []float64, x float64) (lo, hi float64) {
+	n := float64(len(xs))
+	return float64(sort.SearchFloat64s(xs, x)) / n, float64(sort.Search(len(xs), func(i int) bool { return xs[i] > x })) / n
+}
+
+// rank returns the mid-rank of "x" in sorted "xs"
+func rank(xs []float64, x float64) float64 {
+	lo, hi := ranks(xs, x)
+	return (lo + hi) / 2
+}
+
+func TestCountMin(t *testing.T) {
+	tests := []struct {
+		eps, delta float64
+	}{
+		{0.01, 0.01},
+		{0.001, 0.01},
+		{0.005, 0.001},
+	}
+	vals, counts := zipf(1, 100000, 50000)
+	for _, tt := range tests {
+		cm := NewCountMin(tt.eps, tt.delta)
+		for _, v := range vals {
+			cm.Add(v, 1)
+		}
+		if cm.N != uint64(len(vals)) {
+			t.Errorf("eps %v: N %d, want %d", tt.eps, cm.N, len(vals))
+		}
+		if bound := uint64(tt.eps * float64(len(vals))); cm.Err() > bound+1 {
+			t.Errorf("eps %v: Err %d exceeds eps*N %d", tt.eps, cm.Err(), bound)
+		}
+		over := 0
+		for v, c := range counts {
+			switch est := cm.Count(v); {
+			case est < c:
+				t.Fatalf("eps %v: %q undercount %d < %d", tt.eps, v, est, c)
+			case est-c > cm.Err():
+				over++
+			}
+		}
+		if f := float64(over) / float64(len(counts)); f > tt.delta {
+			t.Errorf("eps %v delta %v: %.4f of values beyond error bound", tt.eps, tt.delta, f)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	tests := []struct {
+		k    int
+		eps  float64
+		seed int64
+	}{
+		{10, 0.001, 2},
+		{25, 0.0005, 3},
+		{5, 0.01, 4},
+	}
+	for _, tt := range tests {
+		vals, counts := zipf(tt.seed, 200000, 100000)
+		tk := NewTopK(tt.k, tt.eps, 0.01)
+		for _, v := range vals {
+			tk.Add(v)
+		}
+		items := tk.Items()
+		if len(items) != tt.k {
+			t.Fatalf("k %d: %d items tracked", tt.k, len(items))
+		}
+		for i, it := range items {
+			if c := counts[it.Val]; c > it.Count || c < it.Count-it.Err {
+				t.Errorf("k %d: %q count %d outside [%d,%d]", tt.k, it.Val, c, it.Count-it.Err, it.Count)
+			}
+			if i > 0 && it.Count > items[i-1].Count {
+				t.Errorf("k %d: items not in descending count order", tt.k)
+			}
+		}
+
+		// every value whose true count exceeds the K-th estimate by the error bound is tracked
+		tracked := make(map[string]bool)
+		for _, it := range items {
+			tracked[it.Val] = true
+		}
+		for v, c := range counts {
+			if c > items[tt.k-1].Count+tk.CM.Err() && !tracked[v] {
+				t.Errorf("k %d: heavy hitter %q (%d) not tracked", tt.k, v, c)
+			}
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	vals, counts := zipf(5, 90000, 20000)
+	parts := [][]string{vals[:30000], vals[30000:60000], vals[60000:]}
+
+	cms, tks, hls, tds := [3]*CountMin{}, [3]*TopK{}, [3]*HLL{}, [3]*TDigest{}
+	whole := struct {
+		cm *CountMin
+		hl *HLL
+		td *TDigest
+	}{NewCountMin(0.001, 0.01), NewHLL(12), NewTDigest(100)}
+	r := rand.New(rand.NewSource(6))
+	xs := make([]float64, len(vals))
+	for i := range xs {
+		xs[i] = r.ExpFloat64()
+		whole.cm.Add(vals[i], 1)
+		whole.hl.Add(vals[i])
+		whole.td.Add(xs[i])
+	}
+	// build makes fresh sketches of each part
+	build := func() {
+		for i, p := range parts {
+			cms[i], tks[i], hls[i], tds[i] = NewCountMin(0.001, 0.01), NewTopK(10, 0.001, 0.01), NewHLL(12), NewTDigest(100)
+			for j, v := range p {
+				cms[i].Add(v, 1)
+				tks[i].Add(v)
+				hls[i].Add(v)
+				tds[i].Add(xs[i*30000+j])
+			}
+		}
+	}
+
+	build() // (a+b)+c
+	for _, i := range []int{1, 2} {
+		cms[0].Merge(cms[i])
+		tks[0].Merge(tks[i])
+		hls[0].Merge(hls[i])
+		tds[0].Merge(tds[i])
+	}
+	lcm, ltk, lhl, ltd := cms[0], tks[0], hls[0], tds[0]
+
+	build() // a+(b+c)
+	for _, i := range []int{2, 1} {
+		cms[i-1].Merge(cms[i])
+		tks[i-1].Merge(tks[i])
+		hls[i-1].Merge(hls[i])
+		tds[i-1].Merge(tds[i])
+	}
+	rcm, rtk, rhl, rtd := cms[0], tks[0], hls[0], tds[0]
+
+	for i := range lcm.c {
+		if lcm.c[i] != rcm.c[i] {
+			t.Fatalf("CountMin merge: counter %d (a+b)+c %d, a+(b+c) %d", i, lcm.c[i], rcm.c[i])
+		}
+	}
+	over := 0
+	for v, c := range counts {
+		switch est := lcm.Count(v); {
+		case est < c:
+			t.Fatalf("CountMin merge: %q undercount %d < %d", v, est, c)
+		case est-c > lcm.Err():
+			over++
+		}
+	}
+	if f := float64(over) / float64(len(counts)); f > lcm.Delta {
+		t.Errorf("CountMin merge: %.4f of values beyond error bound", f)
+	}
+	if lcm.N != whole.cm.N {
+		t.Errorf("CountMin merge: N %d, want %d", lcm.N, whole.cm.N)
+	}
+	for i := range whole.hl.reg {
+		if lhl.reg[i] != whole.hl.reg[i] || rhl.reg[i] != whole.hl.reg[i] {
+			t.Fatalf("HLL merge: register %d differs from unmerged estimator", i)
+		}
+	}
+	li, ri := ltk.Items(), rtk.Items()
+	if len(li) != len(ri) {
+		t.Fatalf("TopK merge: %d items vs %d", len(li), len(ri))
+	}
+	for i := range li {
+		if li[i] != ri[i] {
+			t.Errorf("TopK merge item %d: (a+b)+c %v, a+(b+c) %v", i, li[i], ri[i])
+		}
+	}
+	sort.Float64s(xs)
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99} {
+		l, r := ltd.Quantile(q), rtd.Quantile(q)
+		if tol := 0.002 + 0.02*q*(1-q); math.Abs(rank(xs, l)-q) > tol || math.Abs(rank(xs, r)-q) > tol {
+			t.Errorf("TDigest merge q%v: (a+b)+c %.4f (rank %.4f), a+(b+c) %.4f (rank %.4f)", q, l, rank(xs, l), r, rank(xs, r))
+		}
+	}
+	if ltd.N != whole.td.N || ltd.Min != whole.td.Min || ltd.Max != whole.td.Max {
+		t.Errorf("TDigest merge: N/Min/Max %v/%v/%v, want %v/%v/%v", ltd.N, ltd.Min, ltd.Max, whole.td.N, whole.td.Min, whole.td.Max)
+	}
+
+	if NewCountMin(0.01, 0.01).Merge(NewCountMin(0.001, 0.01)) || NewHLL(10).Merge(NewHLL(12)) {
+		t.Errorf("merge of mismatched dimensions accepted")
+	}
+}
+
+func TestHLL(t *testing.T) {
+	tests := []struct {
+		p uint8
+		n int
+	}{
+		{12, 10},
+		{12, 1000},
+		{12, 100000},
+		{14, 1000000},
+		{4, 5000},
+	}
+	for _, tt := range tests {
+		hl := NewHLL(tt.p)
+		for i := 0; i < tt.n; i++ {
+			hl.Add("id-" + strconv.Itoa(i))
+			hl.Add("id-" + strconv.Itoa(i/2)) // duplicates don't count
+		}
+		est, se := float64(hl.Count()), 1.04/math.Sqrt(float64(int(1)<<tt.p))
+		if d := math.Abs(est-float64(tt.n)) / float64(tt.n); d > 3*se {
+			t.Errorf("p %d n %d: estimate %v (%.2f%% error, 3 standard errors %.2f%%)", tt.p, tt.n, est, d*100, 3*se*100)
+		}
+		if hl.Err() == 0 {
+			t.Errorf("p %d n %d: zero error bound", tt.p, tt.n)
+		}
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	tests := []struct {
+		name string
+		gen  func() float64
+	}{
+		{"uniform", r.Float64},
+		{"normal", r.NormFloat64},
+		{"exponential", r.ExpFloat64},
+		{"lognormal", func() float64 { return math.Exp(2 * r.NormFloat64()) }},
+		{"discrete", func() float64 { return float64(r.Intn(10)) }},
+	}
+	for _, tt := range tests {
+		td, xs := NewTDigest(100), make([]float64, 100000)
+		for i := range xs {
+			xs[i] = tt.gen()
+			td.Add(xs[i])
+		}
+		td.Add(math.NaN())
+		sort.Float64s(xs)
+		if td.N != float64(len(xs)) || td.Min != xs[0] || td.Max != xs[len(xs)-1] {
+			t.Errorf("%s: N/Min/Max %v/%v/%v", tt.name, td.N, td.Min, td.Max)
+		}
+		for _, q := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+			// quantile estimates are checked by rank error (tighter in the tails)
+			est, tol := td.Quantile(q), 0.002+0.02*q*(1-q)
+			if lo, hi := ranks(xs, est); hi < q-tol || lo > q+tol {
+				t.Errorf("%s q%v: estimate %v has rank %v-%v", tt.name, q, est, lo, hi)
+			}
+			x := xs[int(q*float64(len(xs)))]
+			if lo, hi := ranks(xs, x); td.CDF(x) < lo-tol || td.CDF(x) > hi+tol {
+				t.Errorf("%s CDF at q%v: %v, true %v-%v", tt.name, q, td.CDF(x), lo, hi)
+			}
+		}
+		if td.Quantile(0) != xs[0] || td.Quantile(1) != xs[len(xs)-1] {
+			t.Errorf("%s: extreme quantiles %v, %v", tt.name, td.Quantile(0), td.Quantile(1))
+		}
+	}
+	if empty := NewTDigest(100); !math.IsNaN(empty.Quantile(0.5)) || !math.IsNaN(empty.CDF(0)) {
+		t.Errorf("empty digest estimates not NaN")
+	}
+}
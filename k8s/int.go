@@ -1 +1 @@
-package k8s
+package k8s
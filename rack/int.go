@@ -1 +1 @@
-package rack
+package rack
@@ -0,0 +1,100 @@
+package xfm
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/sententico/cost/agg"
+	"github.com/sententico/cost/flt"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+func init() {
+	pfax.Register("dist", &pfax.Pipeline[*flt.Dists, *flt.Dists]{
+		Descr: "numeric column distributions (moments, quantiles), output as CSV summary and histogram per column",
+		Fm: pfax.Fmap[*flt.Dists]{
+			"Level 3 CDR": {flt.Dist("CALLDUR,BILLDUR,CHARGE"), "!BILL_IND:!{N},CALLDUR,BILLDUR,CHARGE"},
+			"*":           {flt.Dist(""), ""},
+		},
+		Agg: agg.Dist,
+		Xfm: Dist,
+		Params: []pfax.Xparam{
+			{Name: "cols", Default: "", Descr: "comma-separated numeric columns (format default if unset)"},
+			{Name: "bins", Default: "10", Descr: "histogram bins (equal width from min to max)"},
+			{Name: "comp", Default: "200", Descr: "t-digest compression (higher is more accurate)"},
+		},
+	})
+}
+
+// distQuantiles reported by Dist transform
+var distQuantiles = []float64{0.01, 0.05, 0.25, 0.5, 0.75, 0.95, 0.99}
+
+// Dist transform; CSV output of Dist aggregate: a summary table (count, sum, min/max, mean,
+// standard deviation and estimated quantiles by column), followed by an estimated histogram
+// table for each column
+func Dist(d *flt.Dists) {
+	if d == nil || len(d.Cols) == 0 {
+		fmt.Println("no dist output")
+		return
+	}
+	bins, _ := strconv.Atoi(pfax.Param("bins"))
+	if bins < 1 {
+		bins = 10
+	}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', 8, 64) }
+
+	heads := []string{"column", "count", "sum", "min", "max", "mean", "stddev"}
+	for _, q := range distQuantiles {
+		heads = append(heads, "p"+strconv.FormatFloat(q*100, 'f', -1, 64))
+	}
+	wr := stdWriter(heads)
+	for _, h := range d.Cols {
+		c := d.Col[h]
+		fields := []string{h, strconv.Itoa(c.N)}
+		if c.N == 0 {
+			wr.PutFields(fields)
+			continue
+		}
+		sd := 0.0
+		if c.N > 1 {
+			sd = math.Sqrt(c.M2 / float64(c.N-1))
+		}
+		fields = append(fields, f(c.Sum), f(c.Min), f(c.Max), f(c.Mean), f(sd))
+		for _, q := range distQuantiles {
+			fields = append(fields, f(c.TD.Quantile(q)))
+		}
+		wr.PutFields(fields)
+	}
+	wr.Close()
+
+	for _, h := range d.Cols {
+		c := d.Col[h]
+		if c.N == 0 {
+			continue
+		}
+		fmt.Println()
+		wr = stdWriter([]string{h, "from", "to", "count", "cumulative"})
+		w, prev := (c.Max-c.Min)/float64(bins), 0.0
+		for b := 0; b < bins; b++ {
+			lo, hi, cdf := c.Min+w*float64(b), c.Min+w*float64(b+1), 1.0
+			if b < bins-1 {
+				cdf = c.TD.CDF(hi)
+			} else {
+				hi = c.Max
+			}
+			n := int(math.Round((cdf - prev) * float64(c.N)))
+			wr.PutFields([]string{strconv.Itoa(b + 1), f(lo), f(hi), strconv.Itoa(n), strconv.FormatFloat(cdf*100, 'f', 1, 64) + "%"})
+			if prev = cdf; w == 0 {
+				break
+			}
+		}
+		wr.Close()
+	}
+	for _, h := range d.Cols {
+		if c := d.Col[h]; c.Bad > 0 {
+			fmt.Fprintf(os.Stderr, "%d non-numeric %q values skipped\n", c.Bad, h)
+		}
+	}
+}
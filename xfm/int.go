@@ -1,5 +1,17 @@
-package xfm
-
-import (
-	_ "github.com/sententico/cost/internal/pfax" // stub reference
-)
+package xfm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sententico/cost/csv"
+)
+
+// stdWriter returns a CSV writer to standard output, with heading "heads" written
+func stdWriter(heads []string) *csv.Writer {
+	wr := &csv.Writer{Heads: heads, Heading: true}
+	if e := wr.Open(os.Stdout); e != nil {
+		panic(fmt.Errorf("error writing output: %v", e))
+	}
+	return wr
+}
@@ -1,5 +1,5 @@
-package xfm
-
-// Nil transform; takes no action on its aggregated input
-func Nil(agg interface{}) {
-}
+package xfm
+
+// Nil transform; takes no action on its aggregated input
+func Nil[A any](agg A) {
+}
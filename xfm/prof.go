@@ -0,0 +1,104 @@
+package xfm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sententico/cost/agg"
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/flt"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+func init() {
+	pfax.Register("prof", &pfax.Pipeline[*flt.Profile, *flt.Profile]{
+		Descr: "profile column types, blank rates, distinct values, lengths and E.164 numbers, suggesting format settings",
+		Fm: pfax.Fmap[*flt.Profile]{
+			"*": {flt.Prof, ""},
+		},
+		Agg: agg.Prof,
+		Xfm: Prof,
+		Params: []pfax.Xparam{
+			{Name: "nanp", Default: "1", Descr: "decode 10-digit numbers as NANP (0 to disable)"},
+		},
+	})
+}
+
+// Prof transform; CSV output of Prof aggregate column profiles, followed by suggested format
+// settings (column map of non-blank columns and column type schema) in JSON
+func Prof(p *flt.Profile) {
+	if p == nil || len(p.Cols) == 0 {
+		fmt.Println("no prof output")
+		return
+	}
+	wr := stdWriter([]string{"column", "type", "blank", "distinct", "minlen", "maxlen", "e164", "samples"})
+	pct := func(n, d int) string {
+		if d == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(float64(n)*100/float64(d), 'f', 1, 64) + "%"
+	}
+	var cols []string
+	types := make(csv.ColTypes)
+	for _, h := range p.Cols {
+		c := p.Col[h]
+		nb, t := c.N-c.Blank, profType(c)
+		if nb == 0 {
+			wr.PutFields([]string{h, "blank", pct(c.Blank, c.N), "0", "", "", "", ""})
+			continue
+		}
+		distinct := uint64(len(c.Vals))
+		if c.More {
+			distinct = c.Card.Count()
+		}
+		samples := c.Vals
+		if len(samples) > 5 {
+			samples = samples[:5]
+		}
+		wr.PutFields([]string{h, t, pct(c.Blank, c.N), strconv.FormatUint(distinct, 10), strconv.Itoa(c.MinLen),
+			strconv.Itoa(c.MaxLen), pct(c.E164, nb), strings.Join(samples, "|")})
+		if cols = append(cols, h); t != "str" {
+			types[h] = t
+		}
+	}
+	wr.Close()
+
+	b, _ := json.MarshalIndent(struct {
+		Cols  string
+		Types csv.ColTypes `json:",omitempty"`
+	}{strings.Join(cols, ","), types}, "", "  ")
+	fmt.Printf("\nsuggested settings:\n%s\n", b)
+}
+
+// profType returns the column type specifier inferred from column profile "c": "e164", "int",
+// "dec", "time:<layout>" or "enum:<val>[|<val>]..." if all non-blank values qualify (so typed
+// reads convert every value), otherwise "str"
+func profType(c *flt.ProfCol) string {
+	nb := c.N - c.Blank
+	tl, tn := "", 0
+	for k, n := range c.Class {
+		if strings.HasPrefix(k, "time:") && n > tn {
+			tl, tn = k, n
+		}
+	}
+	switch {
+	case nb == 0:
+		return "str"
+	case c.E164 == nb:
+		return "e164"
+	case c.Class["int"] == nb:
+		return "int"
+	case c.Class["int"]+c.Class["dec"] == nb:
+		return "dec"
+	case tn == nb:
+		return tl
+	case !c.More && nb >= 4*len(c.Vals) && c.Class["str"] > 0:
+		vals := append([]string(nil), c.Vals...)
+		sort.Strings(vals)
+		return "enum:" + strings.Join(vals, "|")
+	}
+	return "str"
+}
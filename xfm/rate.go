@@ -0,0 +1,90 @@
+package xfm
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/sententico/cost/agg"
+	"github.com/sententico/cost/flt"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+func init() {
+	pfax.Register("rate", &pfax.Pipeline[*flt.Rating, *flt.Rating]{
+		Descr: "re-rate CDR calls by rate deck, output as CSV of charged vs re-rated totals by country and prefix, with failures",
+		Fm: pfax.Fmap[*flt.Rating]{
+			"Intelepeer CDR": {flt.Rate(flt.RateCols{Num: "To Country Code+Terminating Phone Number", Dur: "Billable Time",
+				Charge: "Billable Amount", Unit: "min", Deck: "T1"}),
+				"!Call Type:~{^$},To Country Code,Terminating Phone Number,Billable Time,Billable Amount"},
+			"Alvaria CDR": {flt.Rate(flt.RateCols{Num: "toNumber", Dur: "rawDuration", Charge: "charges", Unit: "ms", Deck: "T2",
+				Metered: "meteredDuration", BYOC: 0.00251, FX: 0.86, Currency: "EUR", Places: 3}),
+				"!callDirection:={PSTN_OUTBOUND},toNumber,rawDuration,meteredDuration,charges"},
+			"*": {flt.Rate(flt.RateCols{}), ""},
+		},
+		Agg: agg.Rate,
+		Xfm: Rate,
+		Params: []pfax.Xparam{
+			{Name: "num", Default: "", Descr: "number column, or \"+\"-joined columns (format default if unset)"},
+			{Name: "dur", Default: "", Descr: "call duration column (format default if unset)"},
+			{Name: "charge", Default: "", Descr: "charged amount column (format default if unset)"},
+			{Name: "unit", Default: "", Descr: "duration unit: s, ms or min (format default, or s)"},
+			{Name: "deck", Default: "", Descr: "rate deck file, or built-in T1, T2 or NA (format default, or NA)"},
+			{Name: "metered", Default: "", Descr: "metered duration column for BYOC detection (format default if unset)"},
+			{Name: "byoc", Default: "", Descr: "charge per metered minute below which calls are zero-rated as BYOC (format default if unset)"},
+			{Name: "fx", Default: "", Descr: "rate deck to charge currency multiplier (format default, or 1)"},
+			{Name: "currency", Default: "", Descr: "charge currency (format default, or USD)"},
+			{Name: "incr", Default: "1", Descr: "billing increment seconds as <first>[/<next>]"},
+			{Name: "nanp", Default: "1", Descr: "decode 10-digit numbers as NANP (0 to disable)"},
+			{Name: "fails", Default: "20", Descr: "failed calls listed"},
+		},
+	})
+}
+
+// Rate transform; CSV output of Rate aggregate: charged and re-rated totals (with BYOC zero-rated
+// calls) by country and prefix (ordered by descending re-rated amount) with a total row, followed by
+// failed calls
+func Rate(rt *flt.Rating) {
+	groups := make([]*flt.RateGroup, 0, len(rt.Groups))
+	for _, g := range rt.Groups {
+		groups = append(groups, g)
+	}
+	if len(groups) == 0 && rt.NFail == 0 {
+		fmt.Println("no rate output")
+		return
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Rated != groups[j].Rated {
+			return groups[i].Rated > groups[j].Rated
+		}
+		return groups[i].Prefix < groups[j].Prefix
+	})
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+
+	wr := stdWriter([]string{"country", "prefix", "calls", "byoc", "minutes", "currency", "charged", "rerated", "change"})
+	put := func(g *flt.RateGroup) {
+		wr.PutFields([]string{g.Country, g.Prefix, strconv.Itoa(g.Calls), strconv.Itoa(g.BYOC), f(g.Mins), rt.Currency,
+			f(g.Charged), f(g.Rated), f(g.Rated - g.Charged)})
+	}
+	tot := flt.RateGroup{Country: "TOTAL"}
+	for _, g := range groups {
+		put(g)
+		tot.Calls, tot.BYOC, tot.Mins = tot.Calls+g.Calls, tot.BYOC+g.BYOC, tot.Mins+g.Mins
+		tot.Charged, tot.Rated = tot.Charged+g.Charged, tot.Rated+g.Rated
+	}
+	put(&tot)
+	wr.Close()
+
+	if len(rt.Fails) > 0 {
+		fmt.Println()
+		wr = stdWriter([]string{"file", "line", "number", "failure"})
+		for _, fl := range rt.Fails {
+			wr.PutFields([]string{fl.File, strconv.Itoa(fl.Line), fl.Num, fl.Reason})
+		}
+		wr.Close()
+	}
+	if rt.NFail > 0 {
+		fmt.Fprintf(os.Stderr, "%d calls failed re-rating (%s %s charged)\n", rt.NFail, f(rt.FailChg), rt.Currency)
+	}
+}
@@ -0,0 +1,118 @@
+package xfm
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/sententico/cost/agg"
+	"github.com/sententico/cost/csv"
+	"github.com/sententico/cost/flt"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+func init() {
+	pfax.Register("sum", &pfax.Pipeline[*flt.Sums, *flt.Sums]{
+		Descr: "sum numeric columns grouped by key columns, output as CSV sorted by summed amount",
+		Fm: pfax.Fmap[*flt.Sums]{
+			"AWS CUR": {flt.Sum("lineItem/UnblendedCost"),
+				"lineItem/UsageAccountId,product/ProductName,lineItem/UnblendedCost"},
+			"Level 3 CDR": {flt.Sum("CHARGE,BILLDUR"), "!BILL_IND:!{N},BILLINGNUM,DESTYPEUSED,CHARGE,BILLDUR"},
+			"*":           {flt.Sum(""), ""},
+		},
+		Agg:  agg.Sum,
+		Xfm:  Sum,
+		XfmW: SumW,
+		Params: []pfax.Xparam{
+			{Name: "keys", Default: "", Descr: "comma-separated key columns (remaining column map columns if unset)"},
+			{Name: "sums", Default: "", Descr: "comma-separated summed numeric columns (format default if unset)"},
+			{Name: "sort", Default: "", Descr: "summed column ordering output (first if unset)"},
+			{Name: "prec", Default: "4", Descr: "decimal places of sums"},
+		},
+	})
+}
+
+// Sum transform; CSV output of Sum aggregate groups (key columns, sums and row count) ordered by
+// descending sort column amount
+func Sum(s *flt.Sums) {
+	if s == nil || len(s.Groups) == 0 {
+		fmt.Println("no sum output")
+		return
+	}
+	wr := stdWriter(append(append(append([]string(nil), s.Keys...), s.Cols...), "rows"))
+	defer wr.Close()
+	putSums(wr, nil, s)
+	sumReport(s)
+}
+
+// SumW windowed transform; CSV output of Sum aggregate groups by time window, with windows in
+// order and groups within them ordered as by Sum
+func SumW(m map[int64]*flt.Sums) {
+	var wr *csv.Writer
+	for _, k := range pfax.Windows(m) {
+		s := m[k]
+		if s == nil || len(s.Groups) == 0 {
+			continue
+		}
+		if wr == nil {
+			wr = stdWriter(append(append(append([]string{"window"}, s.Keys...), s.Cols...), "rows"))
+			defer wr.Close()
+		}
+		putSums(wr, []string{pfax.Args.Window.Label(k)}, s)
+		sumReport(s)
+	}
+	if wr == nil {
+		fmt.Println("no sum output")
+	}
+}
+
+// putSums writes groups of Sum aggregate "s" to "wr" ordered by descending sort column amount,
+// each row preceded by "pre" fields
+func putSums(wr *csv.Writer, pre []string, s *flt.Sums) {
+	sc, prec := 0, 4
+	for i, h := range s.Cols {
+		if h == pfax.Param("sort") {
+			sc = i
+		}
+	}
+	if p, e := strconv.Atoi(pfax.Param("prec")); e == nil {
+		prec = p
+	}
+	groups := make([]*flt.SumGroup, 0, len(s.Groups))
+	for _, g := range s.Groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Sums[sc] != groups[j].Sums[sc] {
+			return groups[i].Sums[sc] > groups[j].Sums[sc]
+		}
+		for k := range groups[i].Key {
+			if groups[i].Key[k] != groups[j].Key[k] {
+				return groups[i].Key[k] < groups[j].Key[k]
+			}
+		}
+		return false
+	})
+
+	fields := make([]string, 0, len(pre)+len(s.Keys)+len(s.Cols)+1)
+	for _, g := range groups {
+		fields = append(append(fields[:0], pre...), g.Key...)
+		for _, v := range g.Sums {
+			fields = append(fields, strconv.FormatFloat(v, 'f', prec, 64))
+		}
+		if e := wr.PutFields(append(fields, strconv.Itoa(g.Rows))); e != nil {
+			panic(fmt.Errorf("error writing sum output: %v", e))
+		}
+	}
+}
+
+// sumReport writes skipped value and file counts of Sum aggregate "s" to standard error
+func sumReport(s *flt.Sums) {
+	if s.Bad > 0 {
+		fmt.Fprintf(os.Stderr, "%d non-numeric sum values skipped\n", s.Bad)
+	}
+	if s.Skip > 0 {
+		fmt.Fprintf(os.Stderr, "%d files skipped for key/sum columns inconsistent with %v/%v\n", s.Skip, s.Keys, s.Cols)
+	}
+}
@@ -1,55 +1,100 @@
-package xfm
-
-import (
-	"fmt"
-	"sort"
-	"strings"
-
-	_ "github.com/sententico/cost/internal/pfax" // stub reference
-)
-
-// WC transform; CSV output of WC aggregate
-func WC(agg interface{}) {
-	wc := agg.(map[string]map[string]int)
-	head := make([]string, 0, len(wc))
-	for k := range wc {
-		// bypass output of wide-ranging values
-		if len(wc[k]) < 2000 {
-			head = append(head, k)
-		}
-	}
-	if len(head) == 0 {
-		fmt.Println("no wc output")
-		return
-	}
-	sort.Slice(head, func(i, j int) bool {
-		return head[i] < head[j]
-	})
-	cols := make(map[int][]string, len(head))
-	for c, h := range head {
-		col := make([]string, 0, len(wc[h]))
-		for k := range wc[h] {
-			col = append(col, k)
-		}
-		sort.Slice(col, func(i, j int) bool {
-			return wc[h][col[i]] > wc[h][col[j]]
-		})
-		cols[c] = col
-	}
-
-	for r, ln := 0, strings.Join(head, ",,")+","; strings.TrimLeft(ln, ",") != ""; r++ {
-		fmt.Println(ln)
-		for c, h := range head {
-			switch {
-			case r >= len(cols[c]) && c == 0:
-				ln = ","
-			case r >= len(cols[c]):
-				ln += ",,"
-			case c == 0:
-				ln = fmt.Sprintf("%q,%v", cols[c][r], wc[h][cols[c][r]])
-			default:
-				ln += fmt.Sprintf(",%q,%v", cols[c][r], wc[h][cols[c][r]])
-			}
-		}
-	}
-}
+package xfm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sententico/cost/agg"
+	"github.com/sententico/cost/flt"
+	"github.com/sententico/cost/internal/pfax"
+)
+
+func init() {
+	pfax.Register("wc", &pfax.Pipeline[*flt.Counts, *flt.Counts]{
+		Descr: "count distinct values per column, output as CSV of value/count column pairs (or top values with error bounds and distinct estimates in sketch mode)",
+		Fm: pfax.Fmap[*flt.Counts]{
+			"Level 3 CDR": {flt.WC, "SERVTYPE,!BILL_IND:!{N},BILLINGNUM,DESTYPEUSED"},
+			"*":           {flt.WC, ""},
+		},
+		Agg: agg.WC,
+		Xfm: WC,
+		Params: []pfax.Xparam{
+			{Name: "max", Default: "2000", Descr: "distinct values counted per column (wider columns omitted)"},
+			{Name: "top", Default: "0", Descr: "top values reported per column in sketch mode (exact counts if 0)"},
+			{Name: "eps", Default: "0.001", Descr: "sketch mode count error bound (fraction of column values)"},
+		},
+	})
+}
+
+// WC transform; CSV output of WC aggregate
+func WC(c *flt.Counts) {
+	if c.Top != nil {
+		wcSketch(c)
+		return
+	}
+	wc := c.Vals
+	head, max := make([]string, 0, len(wc)), 0
+	max, _ = strconv.Atoi(pfax.Param("max"))
+	for k := range wc {
+		// bypass output of wide-ranging values
+		if len(wc[k]) < max {
+			head = append(head, k)
+		}
+	}
+	if len(head) == 0 {
+		fmt.Println("no wc output")
+		return
+	}
+	sort.Slice(head, func(i, j int) bool {
+		return head[i] < head[j]
+	})
+	cols := make(map[int][]string, len(head))
+	for c, h := range head {
+		col := make([]string, 0, len(wc[h]))
+		for k := range wc[h] {
+			col = append(col, k)
+		}
+		sort.Slice(col, func(i, j int) bool {
+			return wc[h][col[i]] > wc[h][col[j]]
+		})
+		cols[c] = col
+	}
+
+	for r, ln := 0, strings.Join(head, ",,")+","; strings.TrimLeft(ln, ",") != ""; r++ {
+		fmt.Println(ln)
+		for c, h := range head {
+			switch {
+			case r >= len(cols[c]) && c == 0:
+				ln = ","
+			case r >= len(cols[c]):
+				ln += ",,"
+			case c == 0:
+				ln = fmt.Sprintf("%q,%v", cols[c][r], wc[h][cols[c][r]])
+			default:
+				ln += fmt.Sprintf(",%q,%v", cols[c][r], wc[h][cols[c][r]])
+			}
+		}
+	}
+}
+
+// wcSketch transform; CSV output of WC sketch-mode aggregate: for each column, an estimated
+// distinct value count ("~distinct" value, with standard error) followed by top values with
+// estimated counts and overcount error bounds
+func wcSketch(c *flt.Counts) {
+	head := make([]string, 0, len(c.Top))
+	for k := range c.Top {
+		head = append(head, k)
+	}
+	sort.Strings(head)
+	wr := stdWriter([]string{"column", "value", "count", "error"})
+	defer wr.Close()
+	for _, h := range head {
+		card := c.Card[h]
+		wr.PutFields([]string{h, "~distinct", strconv.FormatUint(card.Count(), 10), strconv.FormatUint(card.Err(), 10)})
+		for _, it := range c.Top[h].Items() {
+			wr.PutFields([]string{h, it.Val, strconv.FormatUint(it.Count, 10), strconv.FormatUint(it.Err, 10)})
+		}
+	}
+}